@@ -0,0 +1,191 @@
+package clients
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/valyala/fasthttp"
+
+	"loadtester/interfaces"
+	"loadtester/types"
+)
+
+// cometTxResult is the CheckTx or DeliverTx outcome of a broadcast.
+type cometTxResult struct {
+	Code      uint32 `json:"code"`
+	Log       string `json:"log"`
+	Codespace string `json:"codespace"`
+}
+
+// cometBroadcastResult covers the results of broadcast_tx_sync/async, which
+// are flat, and broadcast_tx_commit, which nests check_tx and deliver_tx.
+type cometBroadcastResult struct {
+	cometTxResult
+	Hash      string         `json:"hash"`
+	CheckTx   *cometTxResult `json:"check_tx"`
+	DeliverTx *cometTxResult `json:"deliver_tx"`
+	// TxResult replaces DeliverTx since cometbft v0.38
+	TxResult *cometTxResult `json:"tx_result"`
+}
+
+// CometClient broadcasts ethermint-wrapped Ethereum txs through CometBFT rpc,
+// skipping the json-rpc server. Queries are served by the embedded requester.
+type CometClient struct {
+	interfaces.EthRpcRequester
+	cfg       Config
+	cli       *fasthttp.Client
+	wg        *sync.WaitGroup
+	auth      *authenticator
+	retrier   *retrier
+	latencies *latencyRecorder
+}
+
+// NewCometClient creates a new CometClient. queryRpc serves every request
+// which isn't a tx submission.
+func NewCometClient(cfg Config, queryRpc interfaces.EthRpcRequester) *CometClient {
+	if cfg.SendMode == SendModeFireAndForget {
+		log.Fatal().Msgf("send_mode %q isn't supported by the cometbft transport, use cometbft_broadcast_mode = %q instead",
+			SendModeFireAndForget, CometBroadcastAsync)
+	}
+	cli, err := newHTTPClient(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to configure http transport")
+	}
+	auth, err := newAuthenticator(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to configure authentication")
+	}
+	return &CometClient{
+		EthRpcRequester: queryRpc,
+		cfg:             cfg,
+		cli:             cli,
+		wg:              &sync.WaitGroup{},
+		auth:            auth,
+		retrier:         newRetrier(cfg),
+		latencies:       newLatencyRecorder(),
+	}
+}
+
+// EthSendRawTransaction broadcasts the tx with the configured broadcast mode.
+func (cc *CometClient) EthSendRawTransaction(rawTx []byte) error {
+	return cc.retrier.do(func() error {
+		return cc.broadcast(rawTx, cc.cfg.CometBroadcastMode)
+	})
+}
+
+// EthSendRawTransactionOnce broadcasts the tx without retrying it.
+func (cc *CometClient) EthSendRawTransactionOnce(rawTx []byte) error {
+	return cc.broadcast(rawTx, cc.cfg.CometBroadcastMode)
+}
+
+// EthSendRawTransactionNoWaiting broadcasts the tx with broadcast_tx_async,
+// which returns before CheckTx runs.
+func (cc *CometClient) EthSendRawTransactionNoWaiting(rawTx []byte) error {
+	return cc.broadcast(rawTx, CometBroadcastAsync)
+}
+
+func (cc *CometClient) EthSendMultipleRawTransactions(rawTxs [][]byte, cb func(*sync.Mutex, int, types.SendTiming, error)) (failed int64) {
+	return sendMultipleRawTransactions(cc.wg, rawTxs, cc.EthSendRawTransaction, cb)
+}
+
+// RetryStats returns the retry counters of every tx broadcast so far.
+func (cc *CometClient) RetryStats() types.RetryStats {
+	return cc.retrier.snapshot()
+}
+
+// EndpointLatencies returns the latency of the send attempts per endpoint.
+func (cc *CometClient) EndpointLatencies() map[string]types.LatencySummary {
+	return cc.latencies.snapshot()
+}
+
+// ObserveLatencies calls observer with the latency of every send attempt from now on.
+func (cc *CometClient) ObserveLatencies(observer func(endpoint string, d time.Duration)) {
+	cc.latencies.observe(observer)
+}
+
+// MempoolStatus returns the mempool occupancy through the query requester.
+func (cc *CometClient) MempoolStatus() (types.MempoolSample, error) {
+	return mempoolStatusOf(cc.EthRpcRequester)
+}
+
+// CometRpcCall makes the cometbft rpc query through the query requester.
+func (cc *CometClient) CometRpcCall(result interface{}, method string, params map[string]interface{}) error {
+	return cometRpcCallOf(cc.EthRpcRequester, result, method, params)
+}
+
+func (cc *CometClient) broadcast(reqBody []byte, mode string) error {
+	txBytes, err := WrapEthereumTx(reqBody, cc.cfg.EvmDenom)
+	if err != nil {
+		return &types.RpcError{Message: err.Error(), Category: types.CategoryInvalidTx}
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "broadcast_tx_" + mode,
+		"params":  map[string]string{"tx": base64.StdEncoding.EncodeToString(txBytes)},
+		"id":      1,
+	})
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.SetRequestURI(cc.cfg.CometRpcAddr)
+	req.SetBodyRaw(body)
+	req.Header.SetMethod(fasthttp.MethodPost)
+	req.Header.Set("Content-Type", "application/json")
+	cc.auth.apply(req)
+
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	start := time.Now()
+	err = cc.cli.Do(req, resp)
+	cc.latencies.record(RedactURL(cc.cfg.CometRpcAddr), start)
+	if err != nil {
+		return &types.TransportError{Err: err}
+	}
+	return parseCometBroadcastResponse(txBytes, resp.StatusCode(), resp.Body())
+}
+
+// parseCometBroadcastResponse turns a broadcast_tx_* response into a typed error.
+// A successful response must return the sha256 hash of the broadcast tx.
+func parseCometBroadcastResponse(txBytes []byte, status int, body []byte) error {
+	var rpcResp types.RawResponse
+	if err := json.Unmarshal(body, &rpcResp); err != nil {
+		if status != fasthttp.StatusOK {
+			return &types.RpcError{Code: status, Message: string(body), Category: types.CategoryHttpStatus}
+		}
+		return &types.RpcError{Code: status, Message: err.Error(), Category: types.CategoryInvalidResponse}
+	}
+	if rpcResp.Error != nil {
+		// cometbft puts the actual reason, e.g. "tx already exists in cache", into data
+		msg := rpcResp.Error.Message
+		if len(rpcResp.Error.Data) > 0 {
+			msg = fmt.Sprintf("%s: %s", msg, strings.Trim(string(rpcResp.Error.Data), `"`))
+		}
+		return newRpcError(rpcResp.Error.Code, msg)
+	}
+
+	var result cometBroadcastResult
+	if err := json.Unmarshal(rpcResp.Result, &result); err != nil {
+		return &types.RpcError{Code: status, Message: err.Error(), Category: types.CategoryInvalidResponse}
+	}
+	for _, res := range []*cometTxResult{&result.cometTxResult, result.CheckTx, result.DeliverTx, result.TxResult} {
+		if res != nil && res.Code != 0 {
+			return newRpcError(int(res.Code), fmt.Sprintf("%s: %s", res.Codespace, res.Log))
+		}
+	}
+	expected := fmt.Sprintf("%X", sha256.Sum256(txBytes))
+	if !strings.EqualFold(result.Hash, expected) {
+		return &types.RpcError{
+			Code:     status,
+			Message:  fmt.Sprintf("node returned %s, expected %s", result.Hash, expected),
+			Category: types.CategoryHashMismatch,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package clients
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"loadtester/types"
+)
+
+func TestWrapEthereumTx(t *testing.T) {
+	reqBody, hash := signedReqBody(t)
+	txBytes, err := WrapEthereumTx(reqBody, "aphoton")
+	require.NoError(t, err)
+
+	var txRaw txtypes.TxRaw
+	require.NoError(t, txRaw.Unmarshal(txBytes))
+	require.Empty(t, txRaw.Signatures)
+
+	var body txtypes.TxBody
+	require.NoError(t, body.Unmarshal(txRaw.BodyBytes))
+	require.Len(t, body.Messages, 1)
+	require.Equal(t, typeUrlMsgEthereumTx, body.Messages[0].TypeUrl)
+	require.Len(t, body.ExtensionOptions, 1)
+	require.Equal(t, typeUrlExtensionOptionsEthereumTx, body.ExtensionOptions[0].TypeUrl)
+
+	// the msg carries the ethereum tx hash in field 3
+	msg := body.Messages[0].Value
+	var msgHash string
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		msg = msg[n:]
+		if num == 3 {
+			v, m := protowire.ConsumeString(msg)
+			msgHash, msg = v, msg[m:]
+			continue
+		}
+		msg = msg[protowire.ConsumeFieldValue(num, typ, msg):]
+	}
+	require.Equal(t, hash, msgHash)
+
+	var authInfo txtypes.AuthInfo
+	require.NoError(t, authInfo.Unmarshal(txRaw.AuthInfoBytes))
+	require.Equal(t, uint64(21000), authInfo.Fee.GasLimit)
+	require.Equal(t, "21000aphoton", authInfo.Fee.Amount.String())
+}
+
+func TestParseCometBroadcastResponse(t *testing.T) {
+	txBytes := []byte("tx")
+	hash := fmt.Sprintf("%X", sha256.Sum256(txBytes))
+
+	ok := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":{"code":0,"log":"[]","hash":"%s"}}`, hash)
+	require.NoError(t, parseCometBroadcastResponse(txBytes, 200, []byte(ok)))
+
+	inCache := `{"jsonrpc":"2.0","id":1,"error":{"code":-32603,"message":"Internal error","data":"tx already exists in cache"}}`
+	require.Equal(t, types.CategoryAlreadyKnown, types.CategoryOf(parseCometBroadcastResponse(txBytes, 200, []byte(inCache))))
+
+	badNonce := `{"jsonrpc":"2.0","id":1,"result":{"code":32,"codespace":"sdk","log":"invalid nonce; got 1, expected 4: invalid sequence","hash":""}}`
+	var nonceErr *types.NonceError
+	require.ErrorAs(t, parseCometBroadcastResponse(txBytes, 200, []byte(badNonce)), &nonceErr)
+	require.Equal(t, uint64(4), nonceErr.Nonce)
+
+	deliverFailed := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":{"check_tx":{"code":0},"deliver_tx":{"code":11,"log":"out of gas"},"hash":"%s","height":"5"}}`, hash)
+	require.Equal(t, types.CategoryGas, types.CategoryOf(parseCometBroadcastResponse(txBytes, 200, []byte(deliverFailed))))
+
+	// cometbft v0.38 renamed deliver_tx to tx_result
+	txResultFailed := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":{"check_tx":{"code":0},"tx_result":{"code":11,"log":"out of gas"},"hash":"%s","height":"5"}}`, hash)
+	require.Equal(t, types.CategoryGas, types.CategoryOf(parseCometBroadcastResponse(txBytes, 200, []byte(txResultFailed))))
+	txResultOk := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":{"check_tx":{"code":0},"tx_result":{"code":0},"hash":"%s","height":"5"}}`, hash)
+	require.NoError(t, parseCometBroadcastResponse(txBytes, 200, []byte(txResultOk)))
+}
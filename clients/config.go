@@ -0,0 +1,231 @@
+package clients
+
+import "net/url"
+
+const (
+	DefaultEthJsonRpcAddr      = "http://localhost:8545"
+	DefaultHealthCheckInterval = "5s"
+	DefaultHealthCheckTimeout  = "3s"
+	DefaultUnhealthyThreshold  = 3
+	DefaultHealthyThreshold    = 2
+	DefaultMaxBlockLag         = 10
+	DefaultReadTimeout         = "3m"
+	DefaultWriteTimeout        = "3m"
+	DefaultMaxIdleConnDuration = "30m"
+	DefaultMaxConnsPerHost     = 100000
+	DefaultDialConcurrency     = 8192
+	DefaultJwtTokenTTL         = "30s"
+	DefaultRetryMaxAttempts    = 1
+	DefaultRetryInitialBackoff = "100ms"
+	DefaultRetryMaxBackoff     = "2s"
+	DefaultRetryMultiplier     = 2.0
+	DefaultRetryJitter         = 0.2
+	DefaultSendMode            = SendModeWait
+	DefaultFireAndForgetConns  = 64
+	DefaultFireAndForgetSample = 0.01
+	DefaultTransport           = TransportJsonRpc
+	DefaultCometRpcAddr        = "http://localhost:26657"
+	DefaultCometBroadcastMode  = CometBroadcastSync
+	DefaultGrpcAddr            = "localhost:9090"
+	DefaultGrpcBroadcastMode   = GrpcBroadcastSync
+	DefaultIpcConns            = 16
+	DefaultEvmDenom            = "aphoton"
+)
+
+const (
+	// send txs through eth_sendRawTransaction of the json-rpc server
+	TransportJsonRpc = "jsonrpc"
+	// wrap txs into ethermint cosmos txs and broadcast them through cometbft rpc
+	TransportCometBFT = "cometbft"
+	// wrap txs into ethermint cosmos txs and broadcast them through the cosmos sdk grpc tx service
+	TransportGrpc = "grpc"
+	// send every json-rpc request over the unix socket of a node on the same host
+	TransportIpc = "ipc"
+)
+
+const (
+	CometBroadcastSync   = "sync"
+	CometBroadcastAsync  = "async"
+	CometBroadcastCommit = "commit"
+)
+
+const (
+	GrpcBroadcastSync  = "sync"
+	GrpcBroadcastAsync = "async"
+	GrpcBroadcastBlock = "block"
+)
+
+const (
+	// wait for every eth_sendRawTransaction response
+	SendModeWait = "wait"
+	// write requests over pipelined connections without waiting for responses
+	SendModeFireAndForget = "fire_and_forget"
+)
+
+// Config defines the settings shared by all json-rpc clients.
+type Config struct {
+	EthJsonRpcAddr string `toml:"eth_jsonrpc_addr"`
+	// EthJsonRpcAddrs lists additional endpoints. Traffic is spread over
+	// every healthy endpoint in round-robin order.
+	EthJsonRpcAddrs     []string `toml:"eth_jsonrpc_addrs"`
+	HealthCheckInterval string   `toml:"health_check_interval"`
+	HealthCheckTimeout  string   `toml:"health_check_timeout"`
+	// UnhealthyThreshold is the number of consecutive failures (probes or
+	// connection errors) before an endpoint is marked down.
+	UnhealthyThreshold int `toml:"unhealthy_threshold"`
+	// HealthyThreshold is the number of consecutive successful probes before
+	// a down endpoint is marked up again.
+	HealthyThreshold int `toml:"healthy_threshold"`
+	// MaxBlockLag is how many blocks an endpoint may fall behind the highest
+	// known block before it is considered unhealthy.
+	MaxBlockLag uint64 `toml:"max_block_lag"`
+
+	// http transport
+	ReadTimeout         string `toml:"read_timeout"`
+	WriteTimeout        string `toml:"write_timeout"`
+	MaxIdleConnDuration string `toml:"max_idle_conn_duration"`
+	MaxConnsPerHost     int    `toml:"max_conns_per_host"`
+	DialConcurrency     int    `toml:"dial_concurrency"`
+
+	// tls, only used for https endpoints
+	TLSCAFile             string `toml:"tls_ca_file"`
+	TLSCertFile           string `toml:"tls_cert_file"`
+	TLSKeyFile            string `toml:"tls_key_file"`
+	TLSServerName         string `toml:"tls_server_name"`
+	TLSInsecureSkipVerify bool   `toml:"tls_insecure_skip_verify"`
+
+	// authentication, at most one of bearer, basic or jwt should be set
+	Headers           map[string]string `toml:"headers"`
+	AuthBearerToken   string            `toml:"auth_bearer_token"`
+	AuthBasicUser     string            `toml:"auth_basic_user"`
+	AuthBasicPassword string            `toml:"auth_basic_password"`
+	// AuthJwtSecretFile points to a hex encoded secret used to sign HS256
+	// tokens, the same format geth uses for its authenticated rpc.
+	AuthJwtSecretFile string `toml:"auth_jwt_secret_file"`
+	AuthJwtTokenTTL   string `toml:"auth_jwt_token_ttl"`
+
+	// retry of eth_sendRawTransaction on connection errors, timeouts and 5xx.
+	// RetryMaxAttempts includes the first attempt, 1 disables retries.
+	RetryMaxAttempts    int     `toml:"retry_max_attempts"`
+	RetryInitialBackoff string  `toml:"retry_initial_backoff"`
+	RetryMaxBackoff     string  `toml:"retry_max_backoff"`
+	RetryMultiplier     float64 `toml:"retry_multiplier"`
+	// RetryJitter randomizes each backoff by up to this fraction in both directions.
+	RetryJitter float64 `toml:"retry_jitter"`
+
+	SendMode           string `toml:"send_mode"`
+	FireAndForgetConns int    `toml:"fire_and_forget_conns"`
+	// FireAndForgetSampleRate is the fraction of fire-and-forget responses
+	// which are parsed and checked, 0 only drains them.
+	FireAndForgetSampleRate float64 `toml:"fire_and_forget_sample_rate"`
+
+	// Transport selects how txs are submitted. Queries such as nonces go
+	// through the json-rpc endpoints, or through the socket with ipc.
+	Transport          string `toml:"transport"`
+	CometRpcAddr       string `toml:"cometbft_rpc_addr"`
+	CometBroadcastMode string `toml:"cometbft_broadcast_mode"`
+	GrpcAddr           string `toml:"grpc_addr"`
+	GrpcBroadcastMode  string `toml:"grpc_broadcast_mode"`
+	// GrpcTLS enables tls for the grpc transport, using the tls options above
+	GrpcTLS bool `toml:"grpc_tls"`
+	// IpcPath is the unix socket of the ipc transport, e.g. geth.ipc
+	IpcPath  string `toml:"ipc_path"`
+	IpcConns int    `toml:"ipc_conns"`
+	// EvmDenom is the fee denom of ethermint-wrapped txs
+	EvmDenom string `toml:"evm_denom"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		EthJsonRpcAddr:          DefaultEthJsonRpcAddr,
+		HealthCheckInterval:     DefaultHealthCheckInterval,
+		HealthCheckTimeout:      DefaultHealthCheckTimeout,
+		UnhealthyThreshold:      DefaultUnhealthyThreshold,
+		HealthyThreshold:        DefaultHealthyThreshold,
+		MaxBlockLag:             DefaultMaxBlockLag,
+		ReadTimeout:             DefaultReadTimeout,
+		WriteTimeout:            DefaultWriteTimeout,
+		MaxIdleConnDuration:     DefaultMaxIdleConnDuration,
+		MaxConnsPerHost:         DefaultMaxConnsPerHost,
+		DialConcurrency:         DefaultDialConcurrency,
+		AuthJwtTokenTTL:         DefaultJwtTokenTTL,
+		RetryMaxAttempts:        DefaultRetryMaxAttempts,
+		RetryInitialBackoff:     DefaultRetryInitialBackoff,
+		RetryMaxBackoff:         DefaultRetryMaxBackoff,
+		RetryMultiplier:         DefaultRetryMultiplier,
+		RetryJitter:             DefaultRetryJitter,
+		SendMode:                DefaultSendMode,
+		FireAndForgetConns:      DefaultFireAndForgetConns,
+		FireAndForgetSampleRate: DefaultFireAndForgetSample,
+		Transport:               DefaultTransport,
+		CometRpcAddr:            DefaultCometRpcAddr,
+		CometBroadcastMode:      DefaultCometBroadcastMode,
+		GrpcAddr:                DefaultGrpcAddr,
+		GrpcBroadcastMode:       DefaultGrpcBroadcastMode,
+		IpcConns:                DefaultIpcConns,
+		EvmDenom:                DefaultEvmDenom,
+	}
+}
+
+const masked = "<redacted>"
+
+// Redacted returns the config with its credentials and header values masked,
+// so it can be written into reports. Endpoint URLs often carry API keys in
+// their userinfo, path or query, so those are masked too.
+func (c Config) Redacted() Config {
+	c.EthJsonRpcAddr = RedactURL(c.EthJsonRpcAddr)
+	if len(c.EthJsonRpcAddrs) > 0 {
+		addrs := make([]string, len(c.EthJsonRpcAddrs))
+		for i, addr := range c.EthJsonRpcAddrs {
+			addrs[i] = RedactURL(addr)
+		}
+		c.EthJsonRpcAddrs = addrs
+	}
+	c.CometRpcAddr = RedactURL(c.CometRpcAddr)
+	c.GrpcAddr = RedactURL(c.GrpcAddr)
+	if c.AuthBearerToken != "" {
+		c.AuthBearerToken = masked
+	}
+	if c.AuthBasicPassword != "" {
+		c.AuthBasicPassword = masked
+	}
+	if len(c.Headers) > 0 {
+		headers := make(map[string]string, len(c.Headers))
+		for name := range c.Headers {
+			headers[name] = masked
+		}
+		c.Headers = headers
+	}
+	return c
+}
+
+// RedactURL drops the userinfo of an endpoint URL and masks its path and
+// query. Addresses without a host, like host:port, are returned as they are.
+func RedactURL(addr string) string {
+	u, err := url.Parse(addr)
+	if err != nil || u.Host == "" {
+		return addr
+	}
+	redacted := u.Scheme + "://" + u.Host
+	if u.Path != "" && u.Path != "/" {
+		redacted += "/" + masked
+	}
+	if u.RawQuery != "" {
+		redacted += "?" + masked
+	}
+	return redacted
+}
+
+// Addrs returns every configured json-rpc endpoint without duplicates.
+func (c Config) Addrs() []string {
+	var addrs []string
+	seen := make(map[string]bool)
+	for _, addr := range append([]string{c.EthJsonRpcAddr}, c.EthJsonRpcAddrs...) {
+		if addr == "" || seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
@@ -0,0 +1,135 @@
+package clients
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"loadtester/types"
+)
+
+// endpoint is a single json-rpc address along with its health state.
+type endpoint struct {
+	addr string
+	// name is the address with its credentials redacted, it identifies the
+	// endpoint in logs, reports and metrics
+	name    string
+	healthy atomic.Bool
+	height  atomic.Uint64
+
+	mu       sync.Mutex
+	failures int // consecutive failures while healthy
+	recovers int // consecutive successful probes while unhealthy
+}
+
+// EndpointPool spreads requests over healthy endpoints and keeps track of
+// every health state change.
+type EndpointPool struct {
+	endpoints          []*endpoint
+	next               atomic.Uint64
+	unhealthyThreshold int
+	healthyThreshold   int
+
+	mu     sync.Mutex
+	events []types.FailoverEvent
+}
+
+// NewEndpointPool creates a new EndpointPool. All endpoints start healthy.
+func NewEndpointPool(addrs []string, unhealthyThreshold, healthyThreshold int) *EndpointPool {
+	pool := &EndpointPool{
+		unhealthyThreshold: max(unhealthyThreshold, 1),
+		healthyThreshold:   max(healthyThreshold, 1),
+	}
+	names := make(map[string]int)
+	for _, addr := range addrs {
+		ep := &endpoint{addr: addr, name: RedactURL(addr)}
+		// endpoints only told apart by their credentials keep apart
+		if names[ep.name]++; names[ep.name] > 1 {
+			ep.name = fmt.Sprintf("%s#%d", ep.name, names[ep.name])
+		}
+		ep.healthy.Store(true)
+		pool.endpoints = append(pool.endpoints, ep)
+	}
+	return pool
+}
+
+// pick returns the next healthy endpoint in round-robin order.
+func (p *EndpointPool) pick() (*endpoint, error) {
+	n := uint64(len(p.endpoints))
+	start := p.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		ep := p.endpoints[(start+i)%n]
+		if ep.healthy.Load() {
+			return ep, nil
+		}
+	}
+	return nil, types.ErrorNoHealthyEndpoint
+}
+
+// reportSuccess resets the failure streak of a healthy endpoint.
+func (p *EndpointPool) reportSuccess(ep *endpoint) {
+	ep.mu.Lock()
+	ep.failures = 0
+	ep.mu.Unlock()
+}
+
+// reportFailure records a failed request or probe and marks the endpoint down
+// once the failure streak reaches the unhealthy threshold.
+func (p *EndpointPool) reportFailure(ep *endpoint, reason string) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	ep.recovers = 0
+	if !ep.healthy.Load() {
+		return
+	}
+	ep.failures++
+	if ep.failures >= p.unhealthyThreshold {
+		ep.failures = 0
+		ep.healthy.Store(false)
+		p.record(ep, false, reason)
+	}
+}
+
+// reportRecovery records a successful probe of a down endpoint and marks it
+// up once the success streak reaches the healthy threshold.
+func (p *EndpointPool) reportRecovery(ep *endpoint) {
+	ep.mu.Lock()
+	defer ep.mu.Unlock()
+	if ep.healthy.Load() {
+		ep.failures = 0
+		return
+	}
+	ep.recovers++
+	if ep.recovers >= p.healthyThreshold {
+		ep.recovers = 0
+		ep.healthy.Store(true)
+		p.record(ep, true, "recovered")
+	}
+}
+
+func (p *EndpointPool) record(ep *endpoint, healthy bool, reason string) {
+	event := types.FailoverEvent{
+		Time:     time.Now(),
+		Endpoint: ep.name,
+		Healthy:  healthy,
+		Reason:   reason,
+	}
+	if healthy {
+		log.Info().Str("endpoint", ep.name).Msg("endpoint is back up")
+	} else {
+		log.Warn().Str("endpoint", ep.name).Str("reason", reason).Msg("endpoint marked down, rerouting traffic")
+	}
+	p.mu.Lock()
+	p.events = append(p.events, event)
+	p.mu.Unlock()
+}
+
+// Events returns every failover event recorded so far.
+func (p *EndpointPool) Events() []types.FailoverEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]types.FailoverEvent(nil), p.events...)
+}
@@ -0,0 +1,58 @@
+package clients
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+)
+
+func TestEndpointPoolFailover(t *testing.T) {
+	pool := NewEndpointPool([]string{"http://a", "http://b"}, 2, 2)
+	a, b := pool.endpoints[0], pool.endpoints[1]
+
+	// a single failure keeps the endpoint in rotation
+	pool.reportFailure(a, "connection refused")
+	require.True(t, a.healthy.Load())
+
+	// reaching the threshold marks it down and reroutes all traffic
+	pool.reportFailure(a, "connection refused")
+	require.False(t, a.healthy.Load())
+	for i := 0; i < 10; i++ {
+		ep, err := pool.pick()
+		require.NoError(t, err)
+		require.Equal(t, b, ep)
+	}
+
+	// no endpoint left
+	pool.reportFailure(b, "timeout")
+	pool.reportFailure(b, "timeout")
+	_, err := pool.pick()
+	require.ErrorIs(t, err, types.ErrorNoHealthyEndpoint)
+
+	// recovery needs consecutive successful probes
+	pool.reportRecovery(a)
+	require.False(t, a.healthy.Load())
+	pool.reportRecovery(a)
+	require.True(t, a.healthy.Load())
+
+	events := pool.Events()
+	require.Len(t, events, 3)
+	require.Equal(t, "http://a", events[0].Endpoint)
+	require.False(t, events[0].Healthy)
+	require.Equal(t, "http://b", events[1].Endpoint)
+	require.True(t, events[2].Healthy)
+}
+
+func TestEndpointPoolNames(t *testing.T) {
+	pool := NewEndpointPool([]string{"https://node.example.io/v3/key1", "https://node.example.io/v3/key2", "http://b"}, 1, 1)
+	var names []string
+	for _, ep := range pool.endpoints {
+		names = append(names, ep.name)
+	}
+	require.Equal(t, []string{"https://node.example.io/<redacted>", "https://node.example.io/<redacted>#2", "http://b"}, names)
+
+	pool.reportFailure(pool.endpoints[0], "timeout")
+	require.Equal(t, "https://node.example.io/<redacted>", pool.Events()[0].Endpoint)
+}
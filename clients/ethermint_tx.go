@@ -0,0 +1,148 @@
+package clients
+
+import (
+	"fmt"
+	"math/big"
+
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"loadtester/utils"
+)
+
+// ethermint type urls, see proto/ethermint/evm/v1 of ethermint
+const (
+	typeUrlMsgEthereumTx              = "/ethermint.evm.v1.MsgEthereumTx"
+	typeUrlLegacyTx                   = "/ethermint.evm.v1.LegacyTx"
+	typeUrlDynamicFeeTx               = "/ethermint.evm.v1.DynamicFeeTx"
+	typeUrlExtensionOptionsEthereumTx = "/ethermint.evm.v1.ExtensionOptionsEthereumTx"
+)
+
+// WrapEthereumTx wraps the signed Ethereum tx of an eth_sendRawTransaction
+// request body into an ethermint cosmos tx, the same way ethermint's json-rpc
+// server does (MsgEthereumTx.BuildTx) before broadcasting it.
+func WrapEthereumTx(reqBody []byte, evmDenom string) ([]byte, error) {
+	rawTx, err := utils.RawTxFromReqBody(reqBody)
+	if err != nil {
+		return nil, err
+	}
+	var tx gethtypes.Transaction
+	if err := tx.UnmarshalBinary(rawTx); err != nil {
+		return nil, err
+	}
+
+	txData, fee, err := encodeEthermintTxData(&tx)
+	if err != nil {
+		return nil, err
+	}
+	msg := encodeMsgEthereumTx(txData, tx.Hash().Hex())
+
+	body := txtypes.TxBody{
+		Messages:         []*codectypes.Any{{TypeUrl: typeUrlMsgEthereumTx, Value: msg}},
+		ExtensionOptions: []*codectypes.Any{{TypeUrl: typeUrlExtensionOptionsEthereumTx}},
+	}
+	authInfo := txtypes.AuthInfo{Fee: &txtypes.Fee{GasLimit: tx.Gas()}}
+	if fee.Sign() > 0 {
+		authInfo.Fee.Amount = sdk.Coins{{Denom: evmDenom, Amount: sdk.NewIntFromBigInt(fee)}}
+	}
+	bodyBytes, err := body.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	authInfoBytes, err := authInfo.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	// ethereum txs carry their signature in the msg, the cosmos tx has none
+	txRaw := txtypes.TxRaw{BodyBytes: bodyBytes, AuthInfoBytes: authInfoBytes}
+	return txRaw.Marshal()
+}
+
+// encodeMsgEthereumTx encodes ethermint.evm.v1.MsgEthereumTx. The from field
+// must stay empty and size is deprecated, so only data and hash are set.
+func encodeMsgEthereumTx(txData *codectypes.Any, hash string) []byte {
+	anyBytes, _ := txData.Marshal()
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendBytes(b, anyBytes)
+	b = appendString(b, 3, hash)
+	return b
+}
+
+// encodeEthermintTxData encodes the tx as ethermint LegacyTx or DynamicFeeTx and
+// returns it along with the fee to pay, gas limit times gas price or fee cap.
+// The protobuf messages are encoded by hand to stay independent of ethermint forks.
+func encodeEthermintTxData(tx *gethtypes.Transaction) (*codectypes.Any, *big.Int, error) {
+	to := ""
+	if tx.To() != nil {
+		to = tx.To().Hex()
+	}
+	v, r, s := tx.RawSignatureValues()
+	var b []byte
+	switch tx.Type() {
+	case gethtypes.LegacyTxType:
+		b = appendUint(b, 1, tx.Nonce())
+		b = appendString(b, 2, tx.GasPrice().String())
+		b = appendUint(b, 3, tx.Gas())
+		b = appendString(b, 4, to)
+		b = appendString(b, 5, tx.Value().String())
+		b = appendBytes(b, 6, tx.Data())
+		b = appendBytes(b, 7, v.Bytes())
+		b = appendBytes(b, 8, r.Bytes())
+		b = appendBytes(b, 9, s.Bytes())
+		fee := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
+		return &codectypes.Any{TypeUrl: typeUrlLegacyTx, Value: b}, fee, nil
+	case gethtypes.DynamicFeeTxType:
+		b = appendString(b, 1, tx.ChainId().String())
+		b = appendUint(b, 2, tx.Nonce())
+		b = appendString(b, 3, tx.GasTipCap().String())
+		b = appendString(b, 4, tx.GasFeeCap().String())
+		b = appendUint(b, 5, tx.Gas())
+		b = appendString(b, 6, to)
+		b = appendString(b, 7, tx.Value().String())
+		b = appendBytes(b, 8, tx.Data())
+		for _, tuple := range tx.AccessList() {
+			var t []byte
+			t = appendString(t, 1, tuple.Address.Hex())
+			for _, key := range tuple.StorageKeys {
+				t = appendString(t, 2, key.Hex())
+			}
+			b = protowire.AppendTag(b, 9, protowire.BytesType)
+			b = protowire.AppendBytes(b, t)
+		}
+		b = appendBytes(b, 10, v.Bytes())
+		b = appendBytes(b, 11, r.Bytes())
+		b = appendBytes(b, 12, s.Bytes())
+		fee := new(big.Int).Mul(tx.GasFeeCap(), new(big.Int).SetUint64(tx.Gas()))
+		return &codectypes.Any{TypeUrl: typeUrlDynamicFeeTx, Value: b}, fee, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported tx type %d", tx.Type())
+	}
+}
+
+func appendUint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func appendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
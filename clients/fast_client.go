@@ -2,115 +2,154 @@ package clients
 
 import (
 	"encoding/json"
-	"regexp"
-	"strconv"
+	"fmt"
 	"sync"
-	"sync/atomic"
 	"time"
 
-	"github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/valyala/fasthttp"
+
 	"loadtester/types"
+	"loadtester/utils"
 )
 
 // FastClient sends Ethereum json-rpc using fasthttp.
 type FastClient struct {
-	cli                *fasthttp.Client
-	wg                 *sync.WaitGroup
-	jsonRPCAddr        string
-	insufficientFundRe *regexp.Regexp
-	invalidNonceRe     *regexp.Regexp
+	cfg       Config
+	cli       *fasthttp.Client
+	wg        *sync.WaitGroup
+	pool      *EndpointPool
+	auth      *authenticator
+	retrier   *retrier
+	firer     *firer
+	mempool   *mempoolSampler
+	latencies *latencyRecorder
 }
 
 // NewFastClient creates a new FastClient.
-func NewFastClient(jsonRPCAddr string) *FastClient {
-	// TODO: configure timeouts
-	readTimeout, _ := time.ParseDuration("3m")
-	writeTimeout, _ := time.ParseDuration("3m")
-	maxIdleConnDuration, _ := time.ParseDuration("30m")
-
-	fastClient := &fasthttp.Client{
-		ReadTimeout:         readTimeout,
-		WriteTimeout:        writeTimeout,
-		MaxIdleConnDuration: maxIdleConnDuration,
-		RetryIf: func(request *fasthttp.Request) bool { // no retries
-			return false
-		},
-		NoDefaultUserAgentHeader:      true, // Don't send: User-Agent: fasthttp
-		DisableHeaderNamesNormalizing: true, // If you set the case on your headers correctly you can enable this
-		DisablePathNormalizing:        true,
-		MaxConnsPerHost:               100000,
-		// increase DNS cache time to an hour instead of default minute
-		Dial: (&fasthttp.TCPDialer{
-			Concurrency:      8192,
-			DNSCacheDuration: time.Hour,
-		}).Dial,
+func NewFastClient(cfg Config) *FastClient {
+	fastClient, err := newHTTPClient(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to configure http transport")
+	}
+	auth, err := newAuthenticator(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to configure authentication")
 	}
 
-	return &FastClient{
-		cli:                fastClient,
-		wg:                 &sync.WaitGroup{},
-		jsonRPCAddr:        jsonRPCAddr,
-		insufficientFundRe: regexp.MustCompile(`sender balance < tx cost \(\d+ < \d+\): insufficient fund`),
-		invalidNonceRe:     regexp.MustCompile(`expected (\d+)`),
+	fc := &FastClient{
+		cfg:       cfg,
+		cli:       fastClient,
+		wg:        &sync.WaitGroup{},
+		pool:      NewEndpointPool(cfg.Addrs(), cfg.UnhealthyThreshold, cfg.HealthyThreshold),
+		auth:      auth,
+		retrier:   newRetrier(cfg),
+		latencies: newLatencyRecorder(),
 	}
+	fc.firer = newFirer(fc, cfg)
+	fc.mempool = &mempoolSampler{
+		rpcCall: fc.RpcCall,
+		cometCall: func(result interface{}, method string) error {
+			return fc.CometRpcCall(result, method, nil)
+		},
+	}
+	return fc
 }
 
+// prepareRequest sets the target, body and headers of a json-rpc request.
+func (fc *FastClient) prepareRequest(req *fasthttp.Request, addr string, body []byte) {
+	req.SetRequestURI(addr)
+	req.SetBodyRaw(body)
+	req.Header.SetMethod(fasthttp.MethodPost)
+	req.Header.Set("Content-Type", "application/json")
+	fc.auth.apply(req)
+}
+
+// EthSendRawTransaction sends a tx, retrying transient failures according to
+// the configured retry policy.
 func (fc *FastClient) EthSendRawTransaction(rawTx []byte) error {
+	return fc.retrier.do(func() error {
+		return fc.sendRawTransaction(rawTx)
+	})
+}
+
+// EthSendRawTransactionOnce sends a tx without retrying it.
+func (fc *FastClient) EthSendRawTransactionOnce(rawTx []byte) error {
+	return fc.sendRawTransaction(rawTx)
+}
+
+// sendRawTransaction makes a single eth_sendRawTransaction attempt.
+func (fc *FastClient) sendRawTransaction(rawTx []byte) error {
+	ep, err := fc.pool.pick()
+	if err != nil {
+		return err
+	}
+
 	req := fasthttp.AcquireRequest()
 	defer fasthttp.ReleaseRequest(req)
 
-	req.SetRequestURI(fc.jsonRPCAddr)
-	req.SetBodyRaw(rawTx)
-	req.Header.SetMethod(fasthttp.MethodPost)
-	req.Header.Set("Content-Type", "application/json")
+	fc.prepareRequest(req, ep.addr, rawTx)
 
 	resp := fasthttp.AcquireResponse()
 	defer fasthttp.ReleaseResponse(resp)
 
-	err := fc.cli.Do(req, resp)
-
-	insufficientFund := fc.insufficientFundRe.Match(resp.Body())
-	invalidNonceMatches := fc.invalidNonceRe.FindStringSubmatch(string(resp.Body()))
+	start := time.Now()
+	err = fc.cli.Do(req, resp)
+	fc.latencies.record(ep.name, start)
 	if err != nil {
-		return err
+		fc.pool.reportFailure(ep, err.Error())
+		return &types.TransportError{Err: err}
 	}
-	var errResp types.ErrResponse
-	if insufficientFund || invalidNonceMatches != nil {
-		if err := json.Unmarshal(resp.Body(), &errResp); err != nil {
-			panic(err)
+	if resp.StatusCode() >= fasthttp.StatusInternalServerError {
+		fc.pool.reportFailure(ep, fmt.Sprintf("http status %d", resp.StatusCode()))
+	} else {
+		fc.pool.reportSuccess(ep)
+	}
+	return parseSendRawTransactionResponse(rawTx, resp.StatusCode(), resp.Body())
+}
+
+// parseSendRawTransactionResponse turns an eth_sendRawTransaction response into
+// a typed error. A successful response must return the locally computed hash.
+func parseSendRawTransactionResponse(reqBody []byte, status int, body []byte) error {
+	var rpcResp types.RawResponse
+	if err := json.Unmarshal(body, &rpcResp); err != nil {
+		if status != fasthttp.StatusOK {
+			return &types.RpcError{Code: status, Message: string(body), Category: types.CategoryHttpStatus}
 		}
+		return &types.RpcError{Code: status, Message: err.Error(), Category: types.CategoryInvalidResponse}
 	}
-	if insufficientFund {
-		return errors.Wrap(types.ErrorInsufficientFund, errResp.Error.Message)
+	if rpcResp.Error != nil {
+		return newRpcError(rpcResp.Error.Code, rpcResp.Error.Message)
 	}
-	if invalidNonceMatches != nil && len(invalidNonceMatches) > 1 {
-		nonce, _ := strconv.Atoi(invalidNonceMatches[1])
-		return &types.NonceError{
-			Message: errResp.Error.Message,
-			Nonce:   uint64(nonce),
+	if status != fasthttp.StatusOK {
+		return &types.RpcError{Code: status, Message: string(body), Category: types.CategoryHttpStatus}
+	}
+
+	var hash common.Hash
+	if err := json.Unmarshal(rpcResp.Result, &hash); err != nil {
+		return &types.RpcError{Code: status, Message: err.Error(), Category: types.CategoryInvalidResponse}
+	}
+	expected, err := utils.TxHashFromReqBody(reqBody)
+	if err != nil {
+		return &types.RpcError{Code: status, Message: err.Error(), Category: types.CategoryInvalidResponse}
+	}
+	if hash != expected {
+		return &types.RpcError{
+			Code:     status,
+			Message:  fmt.Sprintf("node returned %s, expected %s", hash.Hex(), expected.Hex()),
+			Category: types.CategoryHashMismatch,
 		}
 	}
 	return nil
 }
 
+// EthSendRawTransactionNoWaiting writes the tx over a pipelined connection and
+// returns without waiting for the response, which is drained in the background.
 func (fc *FastClient) EthSendRawTransactionNoWaiting(rawTx []byte) error {
-	req := fasthttp.AcquireRequest()
-	defer fasthttp.ReleaseRequest(req)
-
-	req.SetRequestURI(fc.jsonRPCAddr)
-	req.SetBodyRaw(rawTx)
-	req.Header.SetMethod(fasthttp.MethodPost)
-	req.Header.Set("Content-Type", "application/json")
-
-	err := fc.cli.Do(req, nil)
-	if err != nil {
-		return err
-	}
-	return nil
+	return fc.firer.fire(rawTx)
 }
 
 func (fc *FastClient) EthPendingNonce(addr common.Address) (uint64, error) {
@@ -120,22 +159,25 @@ func (fc *FastClient) EthPendingNonce(addr common.Address) (uint64, error) {
 		"params":  []interface{}{addr, "latest"},
 		"id":      1,
 	})
+	ep, err := fc.pool.pick()
+	if err != nil {
+		return 0, err
+	}
+
 	req := fasthttp.AcquireRequest()
 	defer fasthttp.ReleaseRequest(req)
 
-	req.SetRequestURI(fc.jsonRPCAddr)
-	req.SetBodyRaw(reqBody)
-	req.Header.SetMethod(fasthttp.MethodPost)
-	req.Header.Set("Content-Type", "application/json")
+	fc.prepareRequest(req, ep.addr, reqBody)
 
 	resp := fasthttp.AcquireResponse()
 	defer fasthttp.ReleaseResponse(resp)
 
-	err := fc.cli.Do(req, resp)
-
+	err = fc.cli.Do(req, resp)
 	if err != nil {
+		fc.pool.reportFailure(ep, err.Error())
 		return 0, err
 	}
+	fc.pool.reportSuccess(ep)
 	var nonceResp types.Response
 	var errResp types.ErrResponse
 	if err := json.Unmarshal(resp.Body(), &nonceResp); err != nil {
@@ -154,24 +196,65 @@ func (fc *FastClient) EthPendingNonce(addr common.Address) (uint64, error) {
 	return nonce, nil
 }
 
-func (fc *FastClient) EthSendMultipleRawTransactions(rawTxs [][]byte, cb func(*sync.Mutex, int)) (failed int64) {
-	mu := sync.Mutex{}
-	for i, rawTx := range rawTxs {
-		fc.wg.Add(1)
-		go func(w *sync.WaitGroup, mut *sync.Mutex, idx int, data []byte) {
-			defer w.Done()
-
-			err := fc.EthSendRawTransaction(data)
-			// TODO: Make NoWaiting version
-			// err := ctx.fastClient.EthSendRawTransactionNoWaiting(ctx, data)
-			if err != nil {
-				atomic.AddInt64(&failed, 1)
-				log.Err(err).Msg("failed to send transaction")
-				return
-			}
-			cb(&mu, idx)
-		}(fc.wg, &mu, i, rawTx)
-	}
-	fc.wg.Wait()
-	return failed
+// RpcCall makes a json-rpc query against a healthy endpoint.
+func (fc *FastClient) RpcCall(result interface{}, method string, params ...interface{}) error {
+	ep, err := fc.pool.pick()
+	if err != nil {
+		return err
+	}
+	if params == nil {
+		params = []interface{}{}
+	}
+	err = fc.call(ep.addr, method, params, result, utils.MustPareDuration(fc.cfg.ReadTimeout))
+	if err != nil && !errors.Is(err, types.ErrorRpcCallFailed) {
+		fc.pool.reportFailure(ep, err.Error())
+	}
+	return err
+}
+
+// CometRpcCall makes a cometbft rpc query against the configured cometbft endpoint.
+func (fc *FastClient) CometRpcCall(result interface{}, method string, params map[string]interface{}) error {
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	return fc.call(fc.cfg.CometRpcAddr, method, params, result, utils.MustPareDuration(fc.cfg.ReadTimeout))
+}
+
+func (fc *FastClient) EthSendMultipleRawTransactions(rawTxs [][]byte, cb func(*sync.Mutex, int, types.SendTiming, error)) (failed int64) {
+	if fc.cfg.SendMode != SendModeFireAndForget {
+		return sendMultipleRawTransactions(fc.wg, rawTxs, fc.EthSendRawTransaction, cb)
+	}
+	return sendMultipleRawTransactionsNoWaiting(fc.wg, rawTxs, fc.EthSendRawTransactionNoWaiting, cb)
+}
+
+// RetryStats returns the retry counters of every tx sent so far.
+func (fc *FastClient) RetryStats() types.RetryStats {
+	return fc.retrier.snapshot()
+}
+
+// FireAndForgetStats returns the offered load of fire-and-forget sends, ok is
+// false when fire-and-forget mode is disabled.
+func (fc *FastClient) FireAndForgetStats() (stats types.FireAndForgetStats, ok bool) {
+	return fc.firer.snapshot(), fc.cfg.SendMode == SendModeFireAndForget
+}
+
+// MempoolStatus returns the mempool occupancy from txpool_status, or from
+// cometbft num_unconfirmed_txs when the txpool namespace isn't enabled.
+func (fc *FastClient) MempoolStatus() (types.MempoolSample, error) {
+	return fc.mempool.sample()
+}
+
+// EndpointLatencies returns the latency of the send attempts per endpoint.
+func (fc *FastClient) EndpointLatencies() map[string]types.LatencySummary {
+	return fc.latencies.snapshot()
+}
+
+// ObserveLatencies calls observer with the latency of every send attempt from now on.
+func (fc *FastClient) ObserveLatencies(observer func(endpoint string, d time.Duration)) {
+	fc.latencies.observe(observer)
+}
+
+// FailoverEvents returns every endpoint health change recorded so far.
+func (fc *FastClient) FailoverEvents() []types.FailoverEvent {
+	return fc.pool.Events()
 }
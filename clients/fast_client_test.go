@@ -0,0 +1,118 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+	"loadtester/utils"
+)
+
+func TestParseSendRawTransactionResponse(t *testing.T) {
+	reqBody, hash := signedReqBody(t)
+
+	errBody := func(code int, msg string) string {
+		return fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"error":{"code":%d,"message":%q}}`, code, msg)
+	}
+	tcs := []struct {
+		name     string
+		status   int
+		body     string
+		expected types.ErrorCategory
+	}{
+		{"already known", 200, errBody(-32000, "already known"), types.CategoryAlreadyKnown},
+		{"underpriced", 200, errBody(-32000, "transaction underpriced"), types.CategoryUnderpriced},
+		{"gas price too low", 200, errBody(-32000, "gas price too low"), types.CategoryUnderpriced},
+		{"tx too large", 200, errBody(-32000, "tx too large"), types.CategoryTxTooLarge},
+		{"mempool full", 200, errBody(-32000, "mempool is full: number of txs 5000"), types.CategoryMempoolFull},
+		{"insufficient fund", 200, errBody(-32000, "sender balance < tx cost (1 < 2): insufficient funds"), types.CategoryInsufficientFund},
+		{"method not found", 200, errBody(-32601, "the method foo does not exist"), types.CategoryInvalidRequest},
+		{"unknown", 200, errBody(-32000, "something else"), types.CategoryRpcOther},
+		{"bad gateway", 502, "<html>bad gateway</html>", types.CategoryHttpStatus},
+		{"not json", 200, "ok", types.CategoryInvalidResponse},
+		{"hash mismatch", 200, `{"jsonrpc":"2.0","id":1,"result":"0x0000000000000000000000000000000000000000000000000000000000000001"}`, types.CategoryHashMismatch},
+	}
+	for _, tc := range tcs {
+		err := parseSendRawTransactionResponse(reqBody, tc.status, []byte(tc.body))
+		require.Error(t, err, tc.name)
+		require.Equal(t, tc.expected, types.CategoryOf(err), tc.name)
+	}
+
+	// insufficient fund is still detectable through the sentinel error
+	err := parseSendRawTransactionResponse(reqBody, 200, []byte(errBody(-32000, "insufficient funds")))
+	require.ErrorIs(t, err, types.ErrorInsufficientFund)
+
+	// nonce errors carry the nonce the node expects
+	err = parseSendRawTransactionResponse(reqBody, 200, []byte(errBody(-32000, "invalid nonce; got 3, expected 5: invalid sequence")))
+	var nonceErr *types.NonceError
+	require.ErrorAs(t, err, &nonceErr)
+	require.Equal(t, uint64(5), nonceErr.Nonce)
+	err = parseSendRawTransactionResponse(reqBody, 200, []byte(errBody(-32000, "nonce too low: next nonce 7, tx nonce 3")))
+	require.ErrorAs(t, err, &nonceErr)
+	require.Equal(t, uint64(7), nonceErr.Nonce)
+
+	ok := fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"result":%q}`, hash)
+	require.NoError(t, parseSendRawTransactionResponse(reqBody, 200, []byte(ok)))
+}
+
+func signedReqBody(t *testing.T) ([]byte, string) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	to := crypto.PubkeyToAddress(key.PublicKey)
+	signedTx, err := gethtypes.SignTx(gethtypes.NewTx(&gethtypes.LegacyTx{
+		To:       &to,
+		Value:    big.NewInt(1),
+		Gas:      21000,
+		GasPrice: big.NewInt(1),
+	}), gethtypes.NewEIP155Signer(big.NewInt(9000)), key)
+	require.NoError(t, err)
+	marshaled, err := signedTx.MarshalBinary()
+	require.NoError(t, err)
+	reqBody, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "eth_sendRawTransaction",
+		"params":  []string{hexutil.Encode(marshaled)},
+		"id":      1,
+	})
+	require.NoError(t, err)
+	return reqBody, signedTx.Hash().Hex()
+}
+
+func TestFastClientLatencyEndpointRedacted(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		hash, _ := utils.TxHashFromReqBody(body)
+		resp, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": hash.Hex()})
+		_, _ = w.Write(resp)
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.EthJsonRpcAddr = srv.URL + "/v3/secret"
+	fc := NewFastClient(cfg)
+	var observed []string
+	mu := sync.Mutex{}
+	fc.ObserveLatencies(func(endpoint string, _ time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		observed = append(observed, endpoint)
+	})
+
+	reqBody, _ := signedReqBody(t)
+	require.Zero(t, fc.EthSendMultipleRawTransactions([][]byte{reqBody}, func(*sync.Mutex, int, types.SendTiming, error) {}))
+	// the metrics are labelled with what the observer gets
+	require.Equal(t, []string{srv.URL + "/<redacted>"}, observed)
+	require.Contains(t, fc.EndpointLatencies(), srv.URL+"/<redacted>")
+}
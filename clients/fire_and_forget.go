@@ -0,0 +1,241 @@
+package clients
+
+import (
+	"bufio"
+	"crypto/tls"
+	"math/rand"
+	"net"
+	"net/url"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog/log"
+	"github.com/valyala/fasthttp"
+
+	"loadtester/types"
+)
+
+// pendingRequest is a written request waiting for its response. HTTP/1.1
+// answers pipelined requests in order, so responses are matched by position.
+type pendingRequest struct {
+	reqBody []byte // only kept for sampled requests
+	sampled bool
+}
+
+// pipelineConn is a keep-alive connection requests are written to without
+// waiting for their responses. A reader goroutine drains the responses.
+type pipelineConn struct {
+	ep      *endpoint
+	conn    net.Conn
+	mu      sync.Mutex // guards w, pending and closed
+	w       *bufio.Writer
+	pending []pendingRequest
+	closed  bool
+}
+
+// firer writes eth_sendRawTransaction requests over pipelined connections.
+type firer struct {
+	fc         *FastClient
+	sampleRate float64
+	tlsConfig  *tls.Config
+
+	mu    sync.Mutex
+	conns []*pipelineConn
+	next  atomic.Uint64
+
+	stats   types.FireAndForgetStats // counters updated with sync/atomic
+	statsMu sync.Mutex               // guards stats.SampledErrors
+}
+
+func newFirer(fc *FastClient, cfg Config) *firer {
+	tlsConfig, _ := newTLSConfig(cfg) // already validated by newHTTPClient
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+	return &firer{
+		fc:         fc,
+		sampleRate: cfg.FireAndForgetSampleRate,
+		tlsConfig:  tlsConfig,
+		conns:      make([]*pipelineConn, max(cfg.FireAndForgetConns, 1)),
+	}
+}
+
+// fire writes the request and returns as soon as it is flushed to the socket.
+func (f *firer) fire(reqBody []byte) error {
+	slot := int(f.next.Add(1) % uint64(len(f.conns)))
+	pc, err := f.conn(slot)
+	if err != nil {
+		atomic.AddInt64(&f.stats.WriteErrors, 1)
+		return err
+	}
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	f.fc.prepareRequest(req, pc.ep.addr, reqBody)
+
+	pending := pendingRequest{sampled: f.sampleRate > 0 && rand.Float64() < f.sampleRate}
+	if pending.sampled {
+		pending.reqBody = reqBody
+	}
+
+	pc.mu.Lock()
+	if pc.closed {
+		pc.mu.Unlock()
+		atomic.AddInt64(&f.stats.WriteErrors, 1)
+		return types.ErrorConnectionClosed
+	}
+	pc.pending = append(pc.pending, pending)
+	err = req.Write(pc.w)
+	if err == nil {
+		err = pc.w.Flush()
+	}
+	pc.mu.Unlock()
+
+	if err != nil {
+		atomic.AddInt64(&f.stats.WriteErrors, 1)
+		f.fc.pool.reportFailure(pc.ep, err.Error())
+		f.drop(slot, pc)
+		return err
+	}
+	atomic.AddInt64(&f.stats.Written, 1)
+	return nil
+}
+
+// conn returns the connection of the slot, dialing a healthy endpoint if needed.
+// The dial happens outside the lock so a slow endpoint doesn't block the
+// other slots, the first connection set for the slot wins.
+func (f *firer) conn(slot int) (*pipelineConn, error) {
+	f.mu.Lock()
+	pc := f.conns[slot]
+	f.mu.Unlock()
+	if pc != nil {
+		return pc, nil
+	}
+	ep, err := f.fc.pool.pick()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := f.dial(ep.addr)
+	if err != nil {
+		f.fc.pool.reportFailure(ep, err.Error())
+		return nil, &types.TransportError{Err: err}
+	}
+
+	f.mu.Lock()
+	if existing := f.conns[slot]; existing != nil {
+		f.mu.Unlock()
+		_ = conn.Close()
+		return existing, nil
+	}
+	pc = &pipelineConn{ep: ep, conn: conn, w: bufio.NewWriter(conn)}
+	f.conns[slot] = pc
+	f.mu.Unlock()
+	go f.drain(slot, pc)
+	return pc, nil
+}
+
+func (f *firer) dial(addr string) (net.Conn, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+	if u.Port() == "" {
+		if u.Scheme == "https" {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+	if u.Scheme == "https" {
+		tlsConfig := f.tlsConfig.Clone()
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = u.Hostname()
+		}
+		return tls.Dial("tcp", host, tlsConfig)
+	}
+	return net.Dial("tcp", host)
+}
+
+// drain reads every response of the connection in order, parsing only the
+// sampled ones, until the connection fails.
+func (f *firer) drain(slot int, pc *pipelineConn) {
+	br := bufio.NewReader(pc.conn)
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+	for {
+		resp.Reset()
+		if err := resp.Read(br); err != nil {
+			log.Debug().Err(err).Str("endpoint", pc.ep.name).Msg("fire-and-forget connection closed")
+			f.drop(slot, pc)
+			return
+		}
+		pc.mu.Lock()
+		var pending pendingRequest
+		if len(pc.pending) > 0 {
+			pending, pc.pending = pc.pending[0], pc.pending[1:]
+		}
+		pc.mu.Unlock()
+
+		atomic.AddInt64(&f.stats.Responses, 1)
+		if resp.StatusCode() != fasthttp.StatusOK {
+			atomic.AddInt64(&f.stats.Non200, 1)
+		}
+		if pending.sampled {
+			f.check(pending.reqBody, resp)
+		}
+	}
+}
+
+// check parses a sampled response the same way a waiting send would.
+func (f *firer) check(reqBody []byte, resp *fasthttp.Response) {
+	atomic.AddInt64(&f.stats.Sampled, 1)
+	err := parseSendRawTransactionResponse(reqBody, resp.StatusCode(), resp.Body())
+	if err == nil {
+		return
+	}
+	atomic.AddInt64(&f.stats.SampledFailed, 1)
+	f.statsMu.Lock()
+	if f.stats.SampledErrors == nil {
+		f.stats.SampledErrors = make(map[types.ErrorCategory]int64)
+	}
+	f.stats.SampledErrors[types.CategoryOf(err)]++
+	f.statsMu.Unlock()
+}
+
+// drop closes the connection and frees its slot so the next write redials.
+func (f *firer) drop(slot int, pc *pipelineConn) {
+	pc.mu.Lock()
+	if !pc.closed {
+		pc.closed = true
+		atomic.AddInt64(&f.stats.Unanswered, int64(len(pc.pending)))
+		pc.pending = nil
+		_ = pc.conn.Close()
+	}
+	pc.mu.Unlock()
+
+	f.mu.Lock()
+	if f.conns[slot] == pc {
+		f.conns[slot] = nil
+	}
+	f.mu.Unlock()
+}
+
+func (f *firer) snapshot() types.FireAndForgetStats {
+	f.statsMu.Lock()
+	sampledErrors := make(map[types.ErrorCategory]int64, len(f.stats.SampledErrors))
+	for k, v := range f.stats.SampledErrors {
+		sampledErrors[k] = v
+	}
+	f.statsMu.Unlock()
+	return types.FireAndForgetStats{
+		Written:       atomic.LoadInt64(&f.stats.Written),
+		WriteErrors:   atomic.LoadInt64(&f.stats.WriteErrors),
+		Responses:     atomic.LoadInt64(&f.stats.Responses),
+		Non200:        atomic.LoadInt64(&f.stats.Non200),
+		Unanswered:    atomic.LoadInt64(&f.stats.Unanswered),
+		Sampled:       atomic.LoadInt64(&f.stats.Sampled),
+		SampledFailed: atomic.LoadInt64(&f.stats.SampledFailed),
+		SampledErrors: sampledErrors,
+	}
+}
@@ -0,0 +1,61 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+	"loadtester/utils"
+)
+
+func TestFireAndForget(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		hash, err := utils.TxHashFromReqBody(body)
+		require.NoError(t, err)
+		resp, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": hash.Hex()})
+		_, _ = w.Write(resp)
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.EthJsonRpcAddr = srv.URL
+	cfg.SendMode = SendModeFireAndForget
+	cfg.FireAndForgetConns = 4
+	cfg.FireAndForgetSampleRate = 1
+	fc := NewFastClient(cfg)
+
+	n := 50
+	reqBodies := make([][]byte, n)
+	for i := range reqBodies {
+		reqBodies[i], _ = signedReqBody(t)
+	}
+	var written int
+	failed := fc.EthSendMultipleRawTransactions(reqBodies, func(mu *sync.Mutex, _ int, timing types.SendTiming, err error) {
+		if err == nil && timing.Written {
+			mu.Lock()
+			written++
+			mu.Unlock()
+		}
+	})
+	require.Zero(t, failed)
+	require.Equal(t, n, written, "flushed writes are not reported as accepted")
+
+	require.Eventually(t, func() bool {
+		stats, _ := fc.FireAndForgetStats()
+		return stats.Responses == int64(n)
+	}, 5*time.Second, 10*time.Millisecond)
+	stats, enabled := fc.FireAndForgetStats()
+	require.True(t, enabled)
+	require.Equal(t, int64(n), stats.Written)
+	require.Equal(t, int64(n), stats.Sampled)
+	require.Zero(t, stats.SampledFailed, fmt.Sprint(stats.SampledErrors))
+}
@@ -0,0 +1,204 @@
+package clients
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"loadtester/interfaces"
+	"loadtester/types"
+	"loadtester/utils"
+)
+
+var grpcBroadcastModes = map[string]txtypes.BroadcastMode{
+	GrpcBroadcastSync:  txtypes.BroadcastMode_BROADCAST_MODE_SYNC,
+	GrpcBroadcastAsync: txtypes.BroadcastMode_BROADCAST_MODE_ASYNC,
+	GrpcBroadcastBlock: txtypes.BroadcastMode_BROADCAST_MODE_BLOCK,
+}
+
+// gogoCodec marshals the gogoproto messages of the cosmos sdk with their own
+// Marshal and Unmarshal methods.
+type gogoCodec struct{}
+
+func (gogoCodec) Marshal(v interface{}) ([]byte, error) {
+	return v.(interface{ Marshal() ([]byte, error) }).Marshal()
+}
+
+func (gogoCodec) Unmarshal(data []byte, v interface{}) error {
+	return v.(interface{ Unmarshal([]byte) error }).Unmarshal(data)
+}
+
+func (gogoCodec) Name() string {
+	return "proto"
+}
+
+// GrpcClient broadcasts ethermint-wrapped Ethereum txs through the cosmos sdk
+// cosmos.tx.v1beta1.Service/BroadcastTx endpoint. Queries are served by the
+// embedded requester.
+type GrpcClient struct {
+	interfaces.EthRpcRequester
+	cfg       Config
+	conn      *grpc.ClientConn
+	svc       txtypes.ServiceClient
+	mode      txtypes.BroadcastMode
+	auth      *authenticator
+	wg        *sync.WaitGroup
+	retrier   *retrier
+	latencies *latencyRecorder
+}
+
+// NewGrpcClient creates a new GrpcClient. queryRpc serves every request which
+// isn't a tx submission.
+func NewGrpcClient(cfg Config, queryRpc interfaces.EthRpcRequester) *GrpcClient {
+	mode, ok := grpcBroadcastModes[cfg.GrpcBroadcastMode]
+	if !ok {
+		log.Fatal().Msgf("unknown grpc broadcast mode %q", cfg.GrpcBroadcastMode)
+	}
+	if cfg.SendMode == SendModeFireAndForget {
+		log.Fatal().Msgf("send_mode %q isn't supported by the grpc transport, use grpc_broadcast_mode = %q instead",
+			SendModeFireAndForget, GrpcBroadcastAsync)
+	}
+	// grpc is pinned to v1.33.2 for the cosmos sdk, which predates
+	// credentials/insecure. Switch to
+	// grpc.WithTransportCredentials(insecure.NewCredentials()) once the
+	// replace directive is lifted
+	creds := grpc.WithInsecure()
+	if cfg.GrpcTLS {
+		tlsConfig, err := newTLSConfig(cfg)
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to configure grpc tls")
+		}
+		creds = grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))
+	}
+	conn, err := grpc.Dial(cfg.GrpcAddr, creds, grpc.WithDefaultCallOptions(grpc.ForceCodec(gogoCodec{})))
+	if err != nil {
+		log.Fatal().Err(err).Msgf("failed to dial %s", cfg.GrpcAddr)
+	}
+	auth, err := newAuthenticator(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to configure authentication")
+	}
+	return &GrpcClient{
+		EthRpcRequester: queryRpc,
+		cfg:             cfg,
+		conn:            conn,
+		svc:             txtypes.NewServiceClient(conn),
+		mode:            mode,
+		auth:            auth,
+		wg:              &sync.WaitGroup{},
+		retrier:         newRetrier(cfg),
+		latencies:       newLatencyRecorder(),
+	}
+}
+
+// EthSendRawTransaction broadcasts the tx with the configured broadcast mode.
+func (gc *GrpcClient) EthSendRawTransaction(rawTx []byte) error {
+	return gc.retrier.do(func() error {
+		return gc.broadcast(rawTx, gc.mode)
+	})
+}
+
+// EthSendRawTransactionOnce broadcasts the tx without retrying it.
+func (gc *GrpcClient) EthSendRawTransactionOnce(rawTx []byte) error {
+	return gc.broadcast(rawTx, gc.mode)
+}
+
+// EthSendRawTransactionNoWaiting broadcasts the tx with BROADCAST_MODE_ASYNC,
+// which returns before CheckTx runs.
+func (gc *GrpcClient) EthSendRawTransactionNoWaiting(rawTx []byte) error {
+	return gc.broadcast(rawTx, txtypes.BroadcastMode_BROADCAST_MODE_ASYNC)
+}
+
+func (gc *GrpcClient) EthSendMultipleRawTransactions(rawTxs [][]byte, cb func(*sync.Mutex, int, types.SendTiming, error)) (failed int64) {
+	return sendMultipleRawTransactions(gc.wg, rawTxs, gc.EthSendRawTransaction, cb)
+}
+
+// RetryStats returns the retry counters of every tx broadcast so far.
+func (gc *GrpcClient) RetryStats() types.RetryStats {
+	return gc.retrier.snapshot()
+}
+
+// EndpointLatencies returns the latency of the send attempts per endpoint.
+func (gc *GrpcClient) EndpointLatencies() map[string]types.LatencySummary {
+	return gc.latencies.snapshot()
+}
+
+// ObserveLatencies calls observer with the latency of every send attempt from now on.
+func (gc *GrpcClient) ObserveLatencies(observer func(endpoint string, d time.Duration)) {
+	gc.latencies.observe(observer)
+}
+
+// MempoolStatus returns the mempool occupancy through the query requester.
+func (gc *GrpcClient) MempoolStatus() (types.MempoolSample, error) {
+	return mempoolStatusOf(gc.EthRpcRequester)
+}
+
+// CometRpcCall makes the cometbft rpc query through the query requester.
+func (gc *GrpcClient) CometRpcCall(result interface{}, method string, params map[string]interface{}) error {
+	return cometRpcCallOf(gc.EthRpcRequester, result, method, params)
+}
+
+func (gc *GrpcClient) broadcast(reqBody []byte, mode txtypes.BroadcastMode) error {
+	txBytes, err := WrapEthereumTx(reqBody, gc.cfg.EvmDenom)
+	if err != nil {
+		return &types.RpcError{Message: err.Error(), Category: types.CategoryInvalidTx}
+	}
+	ctx, cancel := context.WithTimeout(
+		metadata.NewOutgoingContext(context.Background(), metadata.New(gc.auth.metadata())),
+		utils.MustPareDuration(gc.cfg.ReadTimeout))
+	defer cancel()
+
+	start := time.Now()
+	resp, err := gc.svc.BroadcastTx(ctx, &txtypes.BroadcastTxRequest{TxBytes: txBytes, Mode: mode})
+	gc.latencies.record(RedactURL(gc.cfg.GrpcAddr), start)
+	if err != nil {
+		return grpcError(err)
+	}
+	return checkTxResponse(txBytes, resp.TxResponse)
+}
+
+// grpcError keeps connection errors and timeouts as they are, so they count as
+// transport errors, and classifies every other status.
+func grpcError(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return &types.TransportError{Err: err}
+	}
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return &types.TransportError{Err: err}
+	default:
+		return newRpcError(int(st.Code()), st.Message())
+	}
+}
+
+// checkTxResponse turns a failed CheckTx or DeliverTx into a typed error. A
+// successful response must return the sha256 hash of the broadcast tx.
+func checkTxResponse(txBytes []byte, txResp *sdk.TxResponse) error {
+	if txResp == nil {
+		return &types.RpcError{Message: "empty tx response", Category: types.CategoryInvalidResponse}
+	}
+	if txResp.Code != 0 {
+		return newRpcError(int(txResp.Code), fmt.Sprintf("%s: %s", txResp.Codespace, txResp.RawLog))
+	}
+	expected := fmt.Sprintf("%X", sha256.Sum256(txBytes))
+	if !strings.EqualFold(txResp.TxHash, expected) {
+		return &types.RpcError{
+			Message:  fmt.Sprintf("node returned %s, expected %s", txResp.TxHash, expected),
+			Category: types.CategoryHashMismatch,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package clients
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"loadtester/types"
+)
+
+func TestCheckTxResponse(t *testing.T) {
+	txBytes := []byte("tx")
+	hash := fmt.Sprintf("%X", sha256.Sum256(txBytes))
+
+	require.NoError(t, checkTxResponse(txBytes, &sdk.TxResponse{TxHash: hash}))
+	require.Equal(t, types.CategoryInvalidResponse, types.CategoryOf(checkTxResponse(txBytes, nil)))
+	require.Equal(t, types.CategoryHashMismatch, types.CategoryOf(checkTxResponse(txBytes, &sdk.TxResponse{TxHash: "00"})))
+
+	badNonce := &sdk.TxResponse{Code: 32, Codespace: "sdk", RawLog: "invalid nonce; got 1, expected 4: invalid sequence"}
+	var nonceErr *types.NonceError
+	require.ErrorAs(t, checkTxResponse(txBytes, badNonce), &nonceErr)
+	require.Equal(t, uint64(4), nonceErr.Nonce)
+}
+
+func TestGrpcError(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "connection refused")
+	require.Equal(t, types.CategoryTransport, types.CategoryOf(grpcError(unavailable)))
+
+	inCache := status.Error(codes.Unknown, "tx already exists in cache")
+	require.Equal(t, types.CategoryAlreadyKnown, types.CategoryOf(grpcError(inCache)))
+}
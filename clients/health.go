@@ -0,0 +1,136 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/valyala/fasthttp"
+
+	"loadtester/types"
+	"loadtester/utils"
+)
+
+// probeResult is the outcome of probing a single endpoint.
+type probeResult struct {
+	height  uint64
+	syncing bool
+	err     error
+}
+
+// StartHealthCheck probes every endpoint periodically until the returned stop
+// function is called. Endpoints failing their probes, still syncing or lagging
+// behind are marked down and traffic is rerouted to the remaining ones.
+func (fc *FastClient) StartHealthCheck() (stop func()) {
+	interval := utils.MustPareDuration(fc.cfg.HealthCheckInterval)
+	done := make(chan struct{})
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				fc.checkEndpoints()
+			}
+		}
+	}()
+	log.Debug().Msgf("started health check of %d endpoints every %s", len(fc.pool.endpoints), interval)
+	return func() { close(done) }
+}
+
+func (fc *FastClient) checkEndpoints() {
+	timeout := utils.MustPareDuration(fc.cfg.HealthCheckTimeout)
+	results := make([]probeResult, len(fc.pool.endpoints))
+	wg := sync.WaitGroup{}
+	for i, ep := range fc.pool.endpoints {
+		wg.Add(1)
+		go func(idx int, ep *endpoint) {
+			defer wg.Done()
+			results[idx] = fc.probe(ep, timeout)
+		}(i, ep)
+	}
+	wg.Wait()
+
+	var best uint64
+	for _, res := range results {
+		if res.err == nil && res.height > best {
+			best = res.height
+		}
+	}
+	for i, ep := range fc.pool.endpoints {
+		res := results[i]
+		switch {
+		case res.err != nil:
+			fc.pool.reportFailure(ep, res.err.Error())
+		case res.syncing:
+			fc.pool.reportFailure(ep, "node is syncing")
+		case best-res.height > fc.cfg.MaxBlockLag:
+			fc.pool.reportFailure(ep, fmt.Sprintf("lagging %d blocks behind", best-res.height))
+		default:
+			ep.height.Store(res.height)
+			fc.pool.reportRecovery(ep)
+		}
+	}
+}
+
+// probe queries eth_blockNumber and eth_syncing of the given endpoint.
+func (fc *FastClient) probe(ep *endpoint, timeout time.Duration) probeResult {
+	var hexHeight string
+	if err := fc.call(ep.addr, "eth_blockNumber", []interface{}{}, &hexHeight, timeout); err != nil {
+		return probeResult{err: err}
+	}
+	height, err := hexutil.DecodeUint64(hexHeight)
+	if err != nil {
+		return probeResult{err: err}
+	}
+	// eth_syncing returns false when the node is synced, an object otherwise
+	var syncing json.RawMessage
+	if err := fc.call(ep.addr, "eth_syncing", []interface{}{}, &syncing, timeout); err != nil {
+		return probeResult{err: err}
+	}
+	return probeResult{height: height, syncing: string(syncing) != "false"}
+}
+
+// call sends a single json-rpc request to addr and decodes its result.
+func (fc *FastClient) call(addr, method string, params interface{}, result interface{}, timeout time.Duration) error {
+	reqBody, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+		"id":      1,
+	})
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+
+	fc.prepareRequest(req, addr, reqBody)
+
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(resp)
+
+	if err := fc.cli.DoTimeout(req, resp, timeout); err != nil {
+		return err
+	}
+	if resp.StatusCode() != fasthttp.StatusOK {
+		return fmt.Errorf("%s returned http status %d", method, resp.StatusCode())
+	}
+	var rpcResp struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(resp.Body(), &rpcResp); err != nil {
+		return err
+	}
+	if rpcResp.Error != nil {
+		return errors.Wrap(types.ErrorRpcCallFailed, fmt.Sprintf("%s: %s", method, rpcResp.Error.Message))
+	}
+	return json.Unmarshal(rpcResp.Result, result)
+}
@@ -0,0 +1,296 @@
+package clients
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"loadtester/types"
+	"loadtester/utils"
+)
+
+// ipcRequest is the part of a json-rpc request body which is forwarded over ipc,
+// the id is replaced so responses can be matched on a shared connection.
+type ipcRequest struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	Id      uint64          `json:"id"`
+}
+
+// ipcConn is a unix socket connection carrying concurrent requests. The node
+// streams json responses back in any order, a reader goroutine dispatches them
+// by id.
+type ipcConn struct {
+	conn    net.Conn
+	mu      sync.Mutex // guards w, pending and closed
+	w       *bufio.Writer
+	pending map[uint64]chan json.RawMessage
+	closed  bool
+}
+
+// IpcClient sends Ethereum json-rpc over the unix domain socket of a node on
+// the same host, e.g. geth.ipc, without tcp and http overhead.
+type IpcClient struct {
+	cfg       Config
+	wg        *sync.WaitGroup
+	retrier   *retrier
+	timeout   time.Duration
+	mempool   *mempoolSampler
+	latencies *latencyRecorder
+
+	mu    sync.Mutex
+	conns []*ipcConn
+	next  atomic.Uint64
+	ids   atomic.Uint64
+}
+
+// NewIpcClient creates a new IpcClient, connections are dialed on first use.
+func NewIpcClient(cfg Config) *IpcClient {
+	if cfg.IpcPath == "" {
+		log.Fatal().Msg("ipc_path must be set for the ipc transport")
+	}
+	ic := &IpcClient{
+		cfg:       cfg,
+		wg:        &sync.WaitGroup{},
+		retrier:   newRetrier(cfg),
+		timeout:   utils.MustPareDuration(cfg.ReadTimeout),
+		conns:     make([]*ipcConn, max(cfg.IpcConns, 1)),
+		latencies: newLatencyRecorder(),
+	}
+	ic.mempool = &mempoolSampler{rpcCall: ic.RpcCall}
+	return ic
+}
+
+// EthSendRawTransaction sends a tx, retrying transient failures according to
+// the configured retry policy.
+func (ic *IpcClient) EthSendRawTransaction(rawTx []byte) error {
+	return ic.retrier.do(func() error {
+		return ic.EthSendRawTransactionOnce(rawTx)
+	})
+}
+
+// EthSendRawTransactionOnce sends a tx without retrying it.
+func (ic *IpcClient) EthSendRawTransactionOnce(rawTx []byte) error {
+	start := time.Now()
+	resp, err := ic.call(rawTx, true)
+	ic.latencies.record(ic.cfg.IpcPath, start)
+	if err != nil {
+		return err
+	}
+	return parseSendRawTransactionResponse(rawTx, 200, resp)
+}
+
+// EthSendRawTransactionNoWaiting writes the tx to the socket and returns, the
+// response is discarded by the reader.
+func (ic *IpcClient) EthSendRawTransactionNoWaiting(rawTx []byte) error {
+	_, err := ic.call(rawTx, false)
+	return err
+}
+
+func (ic *IpcClient) EthPendingNonce(addr common.Address) (uint64, error) {
+	reqBody, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "eth_getTransactionCount",
+		"params":  []interface{}{addr, "latest"},
+		"id":      1,
+	})
+	body, err := ic.call(reqBody, true)
+	if err != nil {
+		return 0, err
+	}
+	var resp types.RawResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return 0, errors.Wrap(types.ErrorFailedToFetchNonce, err.Error())
+	}
+	if resp.Error != nil {
+		return 0, errors.Wrap(types.ErrorFailedToFetchNonce, resp.Error.Message)
+	}
+	var nonce hexutil.Uint64
+	if err := json.Unmarshal(resp.Result, &nonce); err != nil {
+		return 0, errors.Wrap(types.ErrorFailedToFetchNonce, err.Error())
+	}
+	return uint64(nonce), nil
+}
+
+// RpcCall makes a json-rpc query over the socket.
+func (ic *IpcClient) RpcCall(result interface{}, method string, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	reqBody, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+		"id":      1,
+	})
+	body, err := ic.call(reqBody, true)
+	if err != nil {
+		return err
+	}
+	var resp types.RawResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return errors.Wrap(types.ErrorRpcCallFailed, fmt.Sprintf("%s: %s", method, resp.Error.Message))
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+func (ic *IpcClient) EthSendMultipleRawTransactions(rawTxs [][]byte, cb func(*sync.Mutex, int, types.SendTiming, error)) (failed int64) {
+	if ic.cfg.SendMode == SendModeFireAndForget {
+		return sendMultipleRawTransactionsNoWaiting(ic.wg, rawTxs, ic.EthSendRawTransactionNoWaiting, cb)
+	}
+	return sendMultipleRawTransactions(ic.wg, rawTxs, ic.EthSendRawTransaction, cb)
+}
+
+// RetryStats returns the retry counters of every tx sent so far.
+func (ic *IpcClient) RetryStats() types.RetryStats {
+	return ic.retrier.snapshot()
+}
+
+// EndpointLatencies returns the latency of the send attempts per endpoint.
+func (ic *IpcClient) EndpointLatencies() map[string]types.LatencySummary {
+	return ic.latencies.snapshot()
+}
+
+// ObserveLatencies calls observer with the latency of every send attempt from now on.
+func (ic *IpcClient) ObserveLatencies(observer func(endpoint string, d time.Duration)) {
+	ic.latencies.observe(observer)
+}
+
+// MempoolStatus returns the mempool occupancy from txpool_status.
+func (ic *IpcClient) MempoolStatus() (types.MempoolSample, error) {
+	return ic.mempool.sample()
+}
+
+// call writes the request of reqBody under a fresh id and, if wait is set,
+// returns the raw response.
+func (ic *IpcClient) call(reqBody []byte, wait bool) (json.RawMessage, error) {
+	var req ipcRequest
+	if err := json.Unmarshal(reqBody, &req); err != nil {
+		return nil, &types.RpcError{Message: err.Error(), Category: types.CategoryInvalidRequest}
+	}
+	req.Jsonrpc = "2.0"
+	req.Id = ic.ids.Add(1)
+	body, _ := json.Marshal(req)
+
+	slot := int(ic.next.Add(1) % uint64(len(ic.conns)))
+	c, err := ic.conn(slot)
+	if err != nil {
+		return nil, &types.TransportError{Err: err}
+	}
+
+	var ch chan json.RawMessage
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, &types.TransportError{Err: types.ErrorConnectionClosed}
+	}
+	if wait {
+		ch = make(chan json.RawMessage, 1)
+		c.pending[req.Id] = ch
+	}
+	_, err = c.w.Write(body)
+	if err == nil {
+		err = c.w.Flush()
+	}
+	c.mu.Unlock()
+	if err != nil {
+		ic.drop(slot, c)
+		return nil, &types.TransportError{Err: err}
+	}
+	if !wait {
+		return nil, nil
+	}
+
+	timer := time.NewTimer(ic.timeout)
+	defer timer.Stop()
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, &types.TransportError{Err: types.ErrorConnectionClosed}
+		}
+		return resp, nil
+	case <-timer.C:
+		c.mu.Lock()
+		delete(c.pending, req.Id)
+		c.mu.Unlock()
+		return nil, &types.TransportError{Err: errors.Errorf("ipc request timed out after %s", ic.timeout)}
+	}
+}
+
+// conn returns the connection of the slot, dialing the socket if needed.
+func (ic *IpcClient) conn(slot int) (*ipcConn, error) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+	if c := ic.conns[slot]; c != nil {
+		return c, nil
+	}
+	conn, err := net.Dial("unix", ic.cfg.IpcPath)
+	if err != nil {
+		return nil, err
+	}
+	c := &ipcConn{conn: conn, w: bufio.NewWriter(conn), pending: make(map[uint64]chan json.RawMessage)}
+	ic.conns[slot] = c
+	go ic.read(slot, c)
+	return c, nil
+}
+
+// read dispatches every response of the connection to its caller until the
+// connection fails. Responses nobody waits for are discarded.
+func (ic *IpcClient) read(slot int, c *ipcConn) {
+	dec := json.NewDecoder(c.conn)
+	for {
+		var msg json.RawMessage
+		if err := dec.Decode(&msg); err != nil {
+			log.Debug().Err(err).Str("path", ic.cfg.IpcPath).Msg("ipc connection closed")
+			ic.drop(slot, c)
+			return
+		}
+		var resp struct {
+			Id uint64 `json:"id"`
+		}
+		if err := json.Unmarshal(msg, &resp); err != nil {
+			continue
+		}
+		c.mu.Lock()
+		ch, ok := c.pending[resp.Id]
+		delete(c.pending, resp.Id)
+		c.mu.Unlock()
+		if ok {
+			ch <- msg
+		}
+	}
+}
+
+// drop closes the connection, fails its waiting calls and frees its slot so
+// the next call redials.
+func (ic *IpcClient) drop(slot int, c *ipcConn) {
+	c.mu.Lock()
+	if !c.closed {
+		c.closed = true
+		for _, ch := range c.pending {
+			close(ch)
+		}
+		c.pending = nil
+		_ = c.conn.Close()
+	}
+	c.mu.Unlock()
+
+	ic.mu.Lock()
+	if ic.conns[slot] == c {
+		ic.conns[slot] = nil
+	}
+	ic.mu.Unlock()
+}
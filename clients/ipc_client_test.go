@@ -0,0 +1,99 @@
+package clients
+
+import (
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+	"loadtester/utils"
+)
+
+// serveIpc answers eth_sendRawTransaction with the tx hash and every other
+// request with 5.
+func serveIpc(t *testing.T, path string) {
+	l, err := net.Listen("unix", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				dec := json.NewDecoder(conn)
+				enc := json.NewEncoder(conn)
+				for {
+					var req ipcRequest
+					if err := dec.Decode(&req); err != nil {
+						return
+					}
+					resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.Id, "result": "0x5"}
+					if req.Method == "eth_sendRawTransaction" {
+						body, _ := json.Marshal(req)
+						hash, _ := utils.TxHashFromReqBody(body)
+						resp["result"] = hash.Hex()
+					}
+					_ = enc.Encode(resp)
+				}
+			}()
+		}
+	}()
+}
+
+func TestIpcClient(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.ipc")
+	serveIpc(t, path)
+
+	cfg := DefaultConfig()
+	cfg.Transport = TransportIpc
+	cfg.IpcPath = path
+	cfg.IpcConns = 2
+	ic := NewIpcClient(cfg)
+
+	nonce, err := ic.EthPendingNonce(common.Address{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), nonce)
+
+	reqBodies := make([][]byte, 20)
+	for i := range reqBodies {
+		reqBodies[i], _ = signedReqBody(t)
+	}
+	failed := ic.EthSendMultipleRawTransactions(reqBodies, func(_ *sync.Mutex, _ int, _ types.SendTiming, _ error) {})
+	require.Zero(t, failed)
+	require.Equal(t, 20, ic.EndpointLatencies()[path].Count)
+}
+
+func TestIpcClientFireAndForget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node.ipc")
+	serveIpc(t, path)
+
+	cfg := DefaultConfig()
+	cfg.Transport = TransportIpc
+	cfg.IpcPath = path
+	cfg.SendMode = SendModeFireAndForget
+	ic := NewIpcClient(cfg)
+
+	reqBodies := make([][]byte, 5)
+	for i := range reqBodies {
+		reqBodies[i], _ = signedReqBody(t)
+	}
+	var written int
+	failed := ic.EthSendMultipleRawTransactions(reqBodies, func(mu *sync.Mutex, _ int, timing types.SendTiming, err error) {
+		require.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		if timing.Written {
+			written++
+		}
+	})
+	require.Zero(t, failed)
+	require.Equal(t, 5, written)
+}
@@ -0,0 +1,51 @@
+package clients
+
+import (
+	"sync"
+	"time"
+
+	"loadtester/types"
+)
+
+// latencyRecorder keeps a latency histogram per endpoint of every send attempt
+// which waited for its response.
+type latencyRecorder struct {
+	mu        sync.Mutex
+	endpoints map[string]*types.Histogram
+	observer  func(endpoint string, d time.Duration)
+}
+
+func newLatencyRecorder() *latencyRecorder {
+	return &latencyRecorder{endpoints: make(map[string]*types.Histogram)}
+}
+
+func (r *latencyRecorder) record(endpoint string, start time.Time) {
+	d := time.Since(start)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, ok := r.endpoints[endpoint]
+	if !ok {
+		h = types.NewHistogram()
+		r.endpoints[endpoint] = h
+	}
+	h.Record(d)
+	if r.observer != nil {
+		r.observer(endpoint, d)
+	}
+}
+
+func (r *latencyRecorder) observe(observer func(endpoint string, d time.Duration)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observer = observer
+}
+
+func (r *latencyRecorder) snapshot() map[string]types.LatencySummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	summaries := make(map[string]types.LatencySummary, len(r.endpoints))
+	for endpoint, h := range r.endpoints {
+		summaries[endpoint] = h.Summary()
+	}
+	return summaries
+}
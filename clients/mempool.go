@@ -0,0 +1,79 @@
+package clients
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	"loadtester/interfaces"
+	"loadtester/types"
+)
+
+// mempoolSampler queries txpool_status and, once it turns out to be
+// unavailable, cometbft num_unconfirmed_txs instead.
+type mempoolSampler struct {
+	rpcCall   func(result interface{}, method string, params ...interface{}) error
+	cometCall func(result interface{}, method string) error // nil without cometbft rpc
+	noTxpool  atomic.Bool
+}
+
+func (m *mempoolSampler) sample() (types.MempoolSample, error) {
+	now := time.Now()
+	if !m.noTxpool.Load() {
+		var status struct {
+			Pending hexutil.Uint64 `json:"pending"`
+			Queued  hexutil.Uint64 `json:"queued"`
+		}
+		err := m.rpcCall(&status, "txpool_status")
+		if err == nil {
+			return types.MempoolSample{
+				Time:    now,
+				Source:  types.MempoolSourceTxpool,
+				Pending: int64(status.Pending),
+				Queued:  int64(status.Queued),
+			}, nil
+		}
+		if m.cometCall == nil || !errors.Is(err, types.ErrorRpcCallFailed) {
+			return types.MempoolSample{}, err
+		}
+		// the txpool namespace isn't enabled, stick to cometbft
+		m.noTxpool.Store(true)
+	}
+
+	var unconfirmed struct {
+		Total      string `json:"total"`
+		TotalBytes string `json:"total_bytes"`
+	}
+	if err := m.cometCall(&unconfirmed, "num_unconfirmed_txs"); err != nil {
+		return types.MempoolSample{}, err
+	}
+	total, _ := strconv.ParseInt(unconfirmed.Total, 10, 64)
+	bytes, _ := strconv.ParseInt(unconfirmed.TotalBytes, 10, 64)
+	return types.MempoolSample{
+		Time:    now,
+		Source:  types.MempoolSourceCometBFT,
+		Pending: total,
+		Bytes:   bytes,
+	}, nil
+}
+
+// cometRpcCallOf makes the cometbft rpc query through rpc if it supports it.
+func cometRpcCallOf(rpc interfaces.EthRpcRequester, result interface{}, method string, params map[string]interface{}) error {
+	caller, ok := rpc.(interfaces.CometRpcCaller)
+	if !ok {
+		return errors.New("cometbft rpc queries aren't supported")
+	}
+	return caller.CometRpcCall(result, method, params)
+}
+
+// mempoolStatusOf queries the mempool through rpc if it supports it.
+func mempoolStatusOf(rpc interfaces.EthRpcRequester) (types.MempoolSample, error) {
+	reporter, ok := rpc.(interfaces.MempoolReporter)
+	if !ok {
+		return types.MempoolSample{}, errors.New("mempool status isn't supported")
+	}
+	return reporter.MempoolStatus()
+}
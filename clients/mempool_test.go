@@ -0,0 +1,42 @@
+package clients
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+)
+
+func TestMempoolSampler(t *testing.T) {
+	txpoolCalls := 0
+	m := &mempoolSampler{
+		rpcCall: func(result interface{}, method string, params ...interface{}) error {
+			txpoolCalls++
+			return errors.Wrap(types.ErrorRpcCallFailed, "txpool_status: the method txpool_status does not exist/is not available")
+		},
+		cometCall: func(result interface{}, method string) error {
+			require.Equal(t, "num_unconfirmed_txs", method)
+			return json.Unmarshal([]byte(`{"n_txs":"0","total":"42","total_bytes":"4200","txs":null}`), result)
+		},
+	}
+	for i := 0; i < 2; i++ {
+		sample, err := m.sample()
+		require.NoError(t, err)
+		require.Equal(t, types.MempoolSourceCometBFT, sample.Source)
+		require.Equal(t, int64(42), sample.Pending)
+		require.Equal(t, int64(4200), sample.Bytes)
+	}
+	require.Equal(t, 1, txpoolCalls) // txpool isn't queried again once unavailable
+
+	m = &mempoolSampler{rpcCall: func(result interface{}, method string, params ...interface{}) error {
+		return json.Unmarshal([]byte(`{"pending":"0x10","queued":"0x2"}`), result)
+	}}
+	sample, err := m.sample()
+	require.NoError(t, err)
+	require.Equal(t, types.MempoolSourceTxpool, sample.Source)
+	require.Equal(t, int64(16), sample.Pending)
+	require.Equal(t, int64(2), sample.Queued)
+}
@@ -0,0 +1,24 @@
+package clients
+
+import (
+	"github.com/rs/zerolog/log"
+
+	"loadtester/interfaces"
+)
+
+// NewEthRpcRequester creates the requester of the configured transport.
+func NewEthRpcRequester(cfg Config) interfaces.EthRpcRequester {
+	switch cfg.Transport {
+	case TransportJsonRpc:
+		return NewFastClient(cfg)
+	case TransportCometBFT:
+		return NewCometClient(cfg, NewFastClient(cfg))
+	case TransportGrpc:
+		return NewGrpcClient(cfg, NewFastClient(cfg))
+	case TransportIpc:
+		return NewIpcClient(cfg)
+	default:
+		log.Fatal().Msgf("unknown transport %q", cfg.Transport)
+		return nil
+	}
+}
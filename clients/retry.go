@@ -0,0 +1,102 @@
+package clients
+
+import (
+	"math"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"loadtester/types"
+	"loadtester/utils"
+)
+
+// retrier retries transient send failures with exponential backoff and jitter.
+type retrier struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	multiplier     float64
+	jitter         float64
+
+	stats types.RetryStats // updated with sync/atomic
+}
+
+func newRetrier(cfg Config) *retrier {
+	return &retrier{
+		maxAttempts:    max(cfg.RetryMaxAttempts, 1),
+		initialBackoff: utils.MustPareDuration(cfg.RetryInitialBackoff),
+		maxBackoff:     utils.MustPareDuration(cfg.RetryMaxBackoff),
+		multiplier:     cfg.RetryMultiplier,
+		jitter:         cfg.RetryJitter,
+	}
+}
+
+// do calls send until it succeeds, fails with a permanent error or runs out of
+// attempts. "already known" on a retry means an earlier attempt reached the
+// mempool even though its response got lost, so it counts as success.
+func (r *retrier) do(send func() error) error {
+	err := send()
+	if err == nil {
+		return nil
+	}
+	atomic.AddInt64(&r.stats.FirstAttemptFailures, 1)
+	if !retriable(err) || r.maxAttempts == 1 {
+		return err
+	}
+
+	atomic.AddInt64(&r.stats.RetriedTxs, 1)
+	for attempt := 1; attempt < r.maxAttempts; attempt++ {
+		time.Sleep(r.backoff(attempt))
+		atomic.AddInt64(&r.stats.RetryAttempts, 1)
+		err = send()
+		if err == nil {
+			atomic.AddInt64(&r.stats.Recovered, 1)
+			return nil
+		}
+		if types.CategoryOf(err) == types.CategoryAlreadyKnown {
+			atomic.AddInt64(&r.stats.AlreadyKnownOnRetry, 1)
+			atomic.AddInt64(&r.stats.Recovered, 1)
+			return nil
+		}
+		if !retriable(err) {
+			return err
+		}
+	}
+	atomic.AddInt64(&r.stats.Exhausted, 1)
+	return err
+}
+
+// backoff returns the delay before the given retry, starting at 1.
+func (r *retrier) backoff(retry int) time.Duration {
+	delay := float64(r.initialBackoff) * math.Pow(r.multiplier, float64(retry-1))
+	delay = math.Min(delay, float64(r.maxBackoff))
+	delay *= 1 + r.jitter*(2*rand.Float64()-1)
+	return time.Duration(delay)
+}
+
+func (r *retrier) snapshot() types.RetryStats {
+	return types.RetryStats{
+		FirstAttemptFailures: atomic.LoadInt64(&r.stats.FirstAttemptFailures),
+		RetriedTxs:           atomic.LoadInt64(&r.stats.RetriedTxs),
+		RetryAttempts:        atomic.LoadInt64(&r.stats.RetryAttempts),
+		Recovered:            atomic.LoadInt64(&r.stats.Recovered),
+		AlreadyKnownOnRetry:  atomic.LoadInt64(&r.stats.AlreadyKnownOnRetry),
+		Exhausted:            atomic.LoadInt64(&r.stats.Exhausted),
+	}
+}
+
+// retriable reports whether err is a connection error, a timeout or a 5xx.
+func retriable(err error) bool {
+	var rpcErr *types.RpcError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Category == types.CategoryHttpStatus && rpcErr.Code >= 500
+	}
+	switch types.CategoryOf(err) {
+	case types.CategoryTransport, types.CategoryNoEndpoint:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,53 @@
+package clients
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+)
+
+func TestRetrier(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.RetryMaxAttempts = 3
+	cfg.RetryInitialBackoff = "1ms"
+	cfg.RetryMaxBackoff = "2ms"
+	r := newRetrier(cfg)
+
+	sequence := func(errs ...error) func() error {
+		i := 0
+		return func() error {
+			err := errs[i]
+			i++
+			return err
+		}
+	}
+	connErr := &types.TransportError{Err: errors.New("dial tcp: connection refused")}
+	badGateway := &types.RpcError{Code: 502, Category: types.CategoryHttpStatus}
+	alreadyKnown := &types.RpcError{Code: -32000, Message: "already known", Category: types.CategoryAlreadyKnown}
+	underpriced := &types.RpcError{Code: -32000, Message: "transaction underpriced", Category: types.CategoryUnderpriced}
+
+	// recovered on the second attempt
+	require.NoError(t, r.do(sequence(connErr, nil)))
+	// a lost response of the first attempt shows up as already known
+	require.NoError(t, r.do(sequence(badGateway, alreadyKnown)))
+	// permanent errors are not retried
+	require.ErrorIs(t, r.do(sequence(underpriced)), underpriced)
+	// nor are errors of unknown origin
+	unknown := errors.New("unexpected")
+	require.Equal(t, types.CategoryOther, types.CategoryOf(unknown))
+	require.ErrorIs(t, r.do(sequence(unknown)), unknown)
+	// attempts are bounded
+	require.ErrorIs(t, r.do(sequence(connErr, connErr, connErr)), connErr)
+
+	require.Equal(t, types.RetryStats{
+		FirstAttemptFailures: 5,
+		RetriedTxs:           3,
+		RetryAttempts:        4,
+		Recovered:            2,
+		AlreadyKnownOnRetry:  1,
+		Exhausted:            1,
+	}, r.snapshot())
+}
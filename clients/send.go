@@ -0,0 +1,80 @@
+package clients
+
+import (
+	"regexp"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"loadtester/types"
+)
+
+// invalidNonceRe extracts the expected nonce of ethermint ("expected 5") and
+// geth ("state: 5", "next nonce 5") nonce errors.
+var invalidNonceRe = regexp.MustCompile(`(?:expected|state:|next nonce) (\d+)`)
+
+// newRpcError classifies an error returned by the node. Nonce errors become
+// a *types.NonceError carrying the expected nonce when the message tells it.
+func newRpcError(code int, message string) error {
+	category := types.ClassifyRpcError(code, message)
+	if category != types.CategoryInvalidNonce {
+		return &types.RpcError{Code: code, Message: message, Category: category}
+	}
+	nonceErr := &types.NonceError{Code: code, Message: message}
+	if matches := invalidNonceRe.FindStringSubmatch(message); len(matches) > 1 {
+		nonce, _ := strconv.ParseUint(matches[1], 10, 64)
+		nonceErr.Nonce, nonceErr.HasNonce = nonce, true
+	}
+	return nonceErr
+}
+
+// sendMultipleRawTransactionsNoWaiting is sendMultipleRawTransactions for a
+// send which doesn't wait for the node's response, the txs written without
+// error are marked Written.
+func sendMultipleRawTransactionsNoWaiting(
+	wg *sync.WaitGroup, rawTxs [][]byte, send func([]byte) error, cb func(*sync.Mutex, int, types.SendTiming, error),
+) (failed int64) {
+	return sendMultipleRawTransactions(wg, rawTxs, send, func(mu *sync.Mutex, idx int, timing types.SendTiming, err error) {
+		timing.Written = err == nil
+		cb(mu, idx, timing, err)
+	})
+}
+
+// sendMultipleRawTransactions sends every tx with its own goroutine and calls
+// cb once per tx with the send timing and error, nil on success.
+func sendMultipleRawTransactions(
+	wg *sync.WaitGroup, rawTxs [][]byte, send func([]byte) error, cb func(*sync.Mutex, int, types.SendTiming, error),
+) (failed int64) {
+	mu := sync.Mutex{}
+	failures := make(map[types.ErrorCategory]int)
+	samples := make(map[types.ErrorCategory]error)
+	for i, rawTx := range rawTxs {
+		wg.Add(1)
+		go func(w *sync.WaitGroup, mut *sync.Mutex, idx int, data []byte) {
+			defer w.Done()
+
+			timing := types.SendTiming{Start: time.Now()}
+			err := send(data)
+			timing.End = time.Now()
+			if err != nil {
+				atomic.AddInt64(&failed, 1)
+				category := types.CategoryOf(err)
+				mut.Lock()
+				failures[category]++
+				samples[category] = err
+				mut.Unlock()
+			}
+			cb(mut, idx, timing, err)
+		}(wg, &mu, i, rawTx)
+	}
+	wg.Wait()
+	// summarize failures once per batch instead of logging every single one,
+	// a restarting node would otherwise flood the output
+	for category, cnt := range failures {
+		log.Error().Err(samples[category]).Int("count", cnt).Msgf("failed to send transaction: %s", category)
+	}
+	return failed
+}
@@ -0,0 +1,163 @@
+package clients
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/valyala/fasthttp"
+
+	"loadtester/utils"
+)
+
+// newHTTPClient creates the fasthttp client with the configured transport settings.
+func newHTTPClient(cfg Config) (*fasthttp.Client, error) {
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &fasthttp.Client{
+		ReadTimeout:         utils.MustPareDuration(cfg.ReadTimeout),
+		WriteTimeout:        utils.MustPareDuration(cfg.WriteTimeout),
+		MaxIdleConnDuration: utils.MustPareDuration(cfg.MaxIdleConnDuration),
+		RetryIf: func(request *fasthttp.Request) bool { // retries are handled by the retrier
+			return false
+		},
+		NoDefaultUserAgentHeader:      true, // Don't send: User-Agent: fasthttp
+		DisableHeaderNamesNormalizing: true, // If you set the case on your headers correctly you can enable this
+		DisablePathNormalizing:        true,
+		MaxConnsPerHost:               cfg.MaxConnsPerHost,
+		TLSConfig:                     tlsConfig,
+		// increase DNS cache time to an hour instead of default minute
+		Dial: (&fasthttp.TCPDialer{
+			Concurrency:      cfg.DialConcurrency,
+			DNSCacheDuration: time.Hour,
+		}).Dial,
+	}, nil
+}
+
+// newTLSConfig returns nil when no tls option is set so fasthttp uses its defaults.
+func newTLSConfig(cfg Config) (*tls.Config, error) {
+	if cfg.TLSCAFile == "" && cfg.TLSCertFile == "" && cfg.TLSServerName == "" && !cfg.TLSInsecureSkipVerify {
+		return nil, nil
+	}
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.TLSServerName,
+		InsecureSkipVerify: cfg.TLSInsecureSkipVerify,
+	}
+	if cfg.TLSCAFile != "" {
+		bz, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read ca bundle")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bz) {
+			return nil, fmt.Errorf("no certificate found in %s", cfg.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// authenticator sets custom and authorization headers on every request.
+type authenticator struct {
+	headers       map[string]string
+	authorization string
+
+	jwtSecret []byte
+	jwtTTL    time.Duration
+	mu        sync.Mutex
+	jwtToken  string
+	jwtIssued time.Time
+}
+
+func newAuthenticator(cfg Config) (*authenticator, error) {
+	a := &authenticator{headers: cfg.Headers}
+	switch {
+	case cfg.AuthJwtSecretFile != "":
+		bz, err := os.ReadFile(cfg.AuthJwtSecretFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read jwt secret")
+		}
+		secret, err := hexutil.Decode(ensureHexPrefix(strings.TrimSpace(string(bz))))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode jwt secret")
+		}
+		a.jwtSecret = secret
+		a.jwtTTL = utils.MustPareDuration(cfg.AuthJwtTokenTTL)
+	case cfg.AuthBearerToken != "":
+		a.authorization = "Bearer " + cfg.AuthBearerToken
+	case cfg.AuthBasicUser != "":
+		creds := base64.StdEncoding.EncodeToString([]byte(cfg.AuthBasicUser + ":" + cfg.AuthBasicPassword))
+		a.authorization = "Basic " + creds
+	}
+	return a, nil
+}
+
+func (a *authenticator) apply(req *fasthttp.Request) {
+	for k, v := range a.headers {
+		req.Header.Set(k, v)
+	}
+	if a.jwtSecret != nil {
+		req.Header.Set("Authorization", "Bearer "+a.token())
+	} else if a.authorization != "" {
+		req.Header.Set("Authorization", a.authorization)
+	}
+}
+
+// metadata returns the same headers as apply, for grpc calls.
+func (a *authenticator) metadata() map[string]string {
+	md := make(map[string]string, len(a.headers)+1)
+	for k, v := range a.headers {
+		md[k] = v
+	}
+	if a.jwtSecret != nil {
+		md["authorization"] = "Bearer " + a.token()
+	} else if a.authorization != "" {
+		md["authorization"] = a.authorization
+	}
+	return md
+}
+
+// token returns a cached HS256 token carrying an "iat" claim, it is re-signed
+// once it gets older than the configured ttl.
+func (a *authenticator) token() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := time.Now()
+	if a.jwtToken != "" && now.Sub(a.jwtIssued) < a.jwtTTL {
+		return a.jwtToken
+	}
+	header, _ := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]int64{"iat": now.Unix()})
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	mac := hmac.New(sha256.New, a.jwtSecret)
+	mac.Write([]byte(unsigned))
+	a.jwtToken = unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	a.jwtIssued = now
+	return a.jwtToken
+}
+
+func ensureHexPrefix(s string) string {
+	if strings.HasPrefix(s, "0x") {
+		return s
+	}
+	return "0x" + s
+}
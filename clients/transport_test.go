@@ -0,0 +1,190 @@
+package clients
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestNewTLSConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	tlsConfig, err := newTLSConfig(cfg)
+	require.NoError(t, err)
+	require.Nil(t, tlsConfig, "fasthttp defaults without tls options")
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	// the server certificate is self-signed, so it is unknown by default
+	require.Error(t, get(t, cfg, srv.URL))
+
+	dir := t.TempDir()
+	cfg.TLSCAFile = writePem(t, dir, "ca.pem", "CERTIFICATE", srv.Certificate().Raw)
+	require.NoError(t, get(t, cfg, srv.URL))
+
+	cfg = DefaultConfig()
+	cfg.TLSInsecureSkipVerify = true
+	require.NoError(t, get(t, cfg, srv.URL))
+
+	cfg = DefaultConfig()
+	cfg.TLSCAFile = writePem(t, dir, "empty.pem", "", nil)
+	_, err = newTLSConfig(cfg)
+	require.Error(t, err)
+}
+
+func TestNewTLSConfigClientCert(t *testing.T) {
+	dir := t.TempDir()
+	clientCert, certFile, keyFile := selfSignedCert(t, dir)
+	pool := x509.NewCertPool()
+	pool.AddCert(clientCert)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: pool}
+	srv.StartTLS()
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.TLSInsecureSkipVerify = true
+	require.Error(t, get(t, cfg, srv.URL), "the server requires a client certificate")
+
+	cfg.TLSCertFile = certFile
+	cfg.TLSKeyFile = keyFile
+	require.NoError(t, get(t, cfg, srv.URL))
+
+	cfg.TLSKeyFile = filepath.Join(dir, "missing.pem")
+	_, err := newTLSConfig(cfg)
+	require.Error(t, err)
+}
+
+func TestAuthenticator(t *testing.T) {
+	var authorization, custom string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		custom = r.Header.Get("X-Api-Key")
+	}))
+	defer srv.Close()
+
+	cfg := DefaultConfig()
+	cfg.Headers = map[string]string{"X-Api-Key": "key"}
+	cfg.AuthBearerToken = "token"
+	require.NoError(t, get(t, cfg, srv.URL))
+	require.Equal(t, "Bearer token", authorization)
+	require.Equal(t, "key", custom)
+
+	cfg = DefaultConfig()
+	cfg.AuthBasicUser = "user"
+	cfg.AuthBasicPassword = "pass"
+	require.NoError(t, get(t, cfg, srv.URL))
+	require.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("user:pass")), authorization)
+
+	cfg = DefaultConfig()
+	require.NoError(t, get(t, cfg, srv.URL))
+	require.Empty(t, authorization)
+}
+
+func TestAuthenticatorJwt(t *testing.T) {
+	secret := strings.Repeat("ab", 32)
+	secretFile := filepath.Join(t.TempDir(), "jwt.hex")
+	require.NoError(t, os.WriteFile(secretFile, []byte(secret+"\n"), 0o600))
+	cfg := DefaultConfig()
+	cfg.AuthJwtSecretFile = secretFile
+	cfg.AuthJwtTokenTTL = "30s"
+	// the jwt secret wins over the other schemes
+	cfg.AuthBearerToken = "token"
+	a, err := newAuthenticator(cfg)
+	require.NoError(t, err)
+
+	before := time.Now().Unix()
+	token := a.token()
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+	mac := hmac.New(sha256.New, a.jwtSecret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	require.Equal(t, base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), parts[2], "HS256 signature")
+	claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var decoded map[string]int64
+	require.NoError(t, json.Unmarshal(claims, &decoded))
+	require.GreaterOrEqual(t, decoded["iat"], before)
+
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	a.apply(req)
+	require.Equal(t, "Bearer "+token, string(req.Header.Peek("Authorization")))
+	require.Equal(t, "Bearer "+token, a.metadata()["authorization"])
+
+	// the token is cached within its ttl and re-signed once expired
+	issued := a.jwtIssued
+	require.Equal(t, token, a.token())
+	require.Equal(t, issued, a.jwtIssued)
+	a.jwtToken = "expired"
+	a.jwtIssued = issued.Add(-time.Minute)
+	require.NotEqual(t, "expired", a.token())
+	require.True(t, a.jwtIssued.After(issued.Add(-time.Second)))
+}
+
+// get sends a request through a client configured like the fast client.
+func get(t *testing.T, cfg Config, url string) error {
+	client, err := newHTTPClient(cfg)
+	require.NoError(t, err)
+	auth, err := newAuthenticator(cfg)
+	require.NoError(t, err)
+	req := fasthttp.AcquireRequest()
+	resp := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseRequest(req)
+	defer fasthttp.ReleaseResponse(resp)
+	req.SetRequestURI(url)
+	auth.apply(req)
+	return client.DoTimeout(req, resp, 5*time.Second)
+}
+
+func writePem(t *testing.T, dir, name, blockType string, der []byte) string {
+	path := filepath.Join(dir, name)
+	var bz []byte
+	if der != nil {
+		bz = pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	}
+	require.NoError(t, os.WriteFile(path, bz, 0o600))
+	return path
+}
+
+func selfSignedCert(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "loadtester"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return cert, writePem(t, dir, "client.pem", "CERTIFICATE", der), writePem(t, dir, "client-key.pem", "EC PRIVATE KEY", keyDer)
+}
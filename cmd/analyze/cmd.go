@@ -0,0 +1,90 @@
+package analyze
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"loadtester/interfaces"
+	"loadtester/monitor"
+	"loadtester/report"
+)
+
+const (
+	flagFrom         = "from"
+	flagTo           = "to"
+	flagBlockResults = "block-results"
+)
+
+func NewAnalyzeCmd(client func() interfaces.EthRpcRequester) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "analyze",
+		Short: "Analyze the throughput and block production of a block range without sending txs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			from, _ := cmd.Flags().GetUint64(flagFrom)
+			to, _ := cmd.Flags().GetUint64(flagTo)
+			withBlockResults, _ := cmd.Flags().GetBool(flagBlockResults)
+			ethRpc := client()
+			if to == 0 {
+				head, err := monitor.BlockNumber(ethRpc)
+				if err != nil {
+					return errors.Wrap(err, "failed to fetch the latest block")
+				}
+				to = head
+			}
+			if from == 0 || from >= to {
+				return errors.Errorf("invalid block range %d-%d, --from must be positive and below --to", from, to)
+			}
+
+			rep, err := Analyze(ethRpc, from, to, withBlockResults)
+			if err != nil {
+				return err
+			}
+			LogResults(rep)
+			return nil
+		},
+	}
+	cmd.Flags().Uint64(flagFrom, 0, "first block of the range")
+	cmd.Flags().Uint64(flagTo, 0, "last block of the range, the latest block if not set")
+	cmd.Flags().Bool(flagBlockResults, false, "also fetch cometbft block_results for tx failure codes")
+	return cmd
+}
+
+// Analyze computes the same block statistics as a live evmtx run for the blocks
+// from, to. The block before from marks the start of the range.
+func Analyze(ethRpc interfaces.EthRpcRequester, from, to uint64, withBlockResults bool) (*report.Report, error) {
+	log.Info().Msgf("fetching blocks %d-%d", from, to)
+	blocks, err := monitor.FetchBlocks(ethRpc, from-1, to)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch blocks")
+	}
+	throughput := monitor.Throughput(blocks, nil)
+	health := monitor.BlockHealth(blocks, time.Time{}, nil)
+	rep := &report.Report{
+		Scenario:    "analyze",
+		TimeSpent:   throughput.Duration,
+		Throughput:  &throughput,
+		BlockHealth: &health,
+	}
+	if withBlockResults {
+		comet, ok := ethRpc.(interfaces.CometRpcCaller)
+		if !ok {
+			return nil, errors.New("the configured transport can't query cometbft rpc")
+		}
+		log.Info().Msgf("fetching block results %d-%d", from, to)
+		results, err := monitor.FetchBlockResults(comet, from, to)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch block results")
+		}
+		rep.BlockResults = &results
+	}
+	return rep, nil
+}
+
+func LogResults(rep *report.Report) {
+	rep.LogThroughput()
+	rep.LogBlockHealth()
+	rep.LogBlockResults()
+}
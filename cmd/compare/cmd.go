@@ -0,0 +1,65 @@
+package compare
+
+import (
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"loadtester/report"
+)
+
+const (
+	flagMaxThroughputDrop    = "max-throughput-drop"
+	flagMaxLatencyIncrease   = "max-latency-increase"
+	flagMaxErrorRateIncrease = "max-error-rate-increase"
+)
+
+func NewCompareCmd(cfg *Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare <baseline.json> <candidate.json>",
+		Short: "Compare two run files and fail on regressions beyond the thresholds",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// the flags override the config, which is only read when the command runs
+			if cmd.Flags().Changed(flagMaxThroughputDrop) {
+				cfg.MaxThroughputDrop, _ = cmd.Flags().GetFloat64(flagMaxThroughputDrop)
+			}
+			if cmd.Flags().Changed(flagMaxLatencyIncrease) {
+				cfg.MaxLatencyIncrease, _ = cmd.Flags().GetFloat64(flagMaxLatencyIncrease)
+			}
+			if cmd.Flags().Changed(flagMaxErrorRateIncrease) {
+				cfg.MaxErrorRateIncrease, _ = cmd.Flags().GetFloat64(flagMaxErrorRateIncrease)
+			}
+
+			baseline, err := report.ReadRunFile(args[0])
+			if err != nil {
+				return err
+			}
+			candidate, err := report.ReadRunFile(args[1])
+			if err != nil {
+				return err
+			}
+			if baseline.Scenario != candidate.Scenario {
+				log.Warn().Msgf("comparing runs of different scenarios: %s and %s", baseline.Scenario, candidate.Scenario)
+			}
+			log.Info().Msgf("baseline %s (%s), candidate %s (%s)", args[0], baseline.Version, args[1], candidate.Version)
+
+			deltas := report.Compare(baseline.Report, candidate.Report, cfg.thresholds())
+			if len(deltas) == 0 {
+				return errors.New("the runs have no metric in common")
+			}
+			report.LogComparison(deltas)
+			// cobra prints the usage along with errors returned by RunE
+			cmd.SilenceUsage = true
+			if n := report.Regressions(deltas); n > 0 {
+				return errors.Errorf("%d of %d metrics regressed", n, len(deltas))
+			}
+			log.Info().Msgf("no regression over %d metrics", len(deltas))
+			return nil
+		},
+	}
+	cmd.Flags().Float64(flagMaxThroughputDrop, cfg.MaxThroughputDrop, "tolerated throughput drop, in percent")
+	cmd.Flags().Float64(flagMaxLatencyIncrease, cfg.MaxLatencyIncrease, "tolerated latency increase, in percent")
+	cmd.Flags().Float64(flagMaxErrorRateIncrease, cfg.MaxErrorRateIncrease, "tolerated error and pending rate increase, in percentage points")
+	return cmd
+}
@@ -0,0 +1,37 @@
+package compare
+
+import "loadtester/report"
+
+const (
+	DefaultMaxThroughputDrop    = 5.0
+	DefaultMaxLatencyIncrease   = 10.0
+	DefaultMaxErrorRateIncrease = 1.0
+)
+
+type Config struct {
+	// MaxThroughputDrop is the tolerated drop of the client and on-chain
+	// throughput, in percent of the baseline
+	MaxThroughputDrop float64 `toml:"max_throughput_drop"`
+	// MaxLatencyIncrease is the tolerated increase of the send and inclusion
+	// latency percentiles, in percent of the baseline
+	MaxLatencyIncrease float64 `toml:"max_latency_increase"`
+	// MaxErrorRateIncrease is the tolerated increase of the error and pending
+	// rates, in percentage points
+	MaxErrorRateIncrease float64 `toml:"max_error_rate_increase"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MaxThroughputDrop:    DefaultMaxThroughputDrop,
+		MaxLatencyIncrease:   DefaultMaxLatencyIncrease,
+		MaxErrorRateIncrease: DefaultMaxErrorRateIncrease,
+	}
+}
+
+func (cfg *Config) thresholds() report.Thresholds {
+	return report.Thresholds{
+		ThroughputDrop:  cfg.MaxThroughputDrop,
+		LatencyIncrease: cfg.MaxLatencyIncrease,
+		RateIncrease:    cfg.MaxErrorRateIncrease,
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"os"
 
 	"github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 
+	"loadtester/clients"
+	"loadtester/cmd/compare"
 	"loadtester/cmd/evmtx"
 	"loadtester/cmd/offchain_feeding"
 )
@@ -14,52 +17,66 @@ var (
 	DefaultConfigPath = "./config.toml"
 )
 
-type CommonConfig struct {
-	EthJsonRpcAddr string `toml:"eth_jsonrpc_addr"`
-}
-
 // Config defines all necessary configuration parameters.
 type Config struct {
-	CommonConfig          CommonConfig            `toml:"common"`
+	CommonConfig          clients.Config          `toml:"common"`
 	EvmTxConfig           evmtx.Config            `toml:"evmtx"`
 	OffchainFeedingConfig offchain_feeding.Config `toml:"offchain_feeding"`
+	CompareConfig         compare.Config          `toml:"compare"`
 }
 
 func DefaultConfig() Config {
 	return Config{
-		CommonConfig: CommonConfig{
-			EthJsonRpcAddr: "http://localhost:8545",
-		},
+		CommonConfig:          clients.DefaultConfig(),
 		EvmTxConfig:           evmtx.DefaultConfig(),
 		OffchainFeedingConfig: offchain_feeding.DefaultConfig(),
+		CompareConfig:         compare.DefaultConfig(),
 	}
 }
 
 func MustRead(configPath string) *Config {
+	cfg, err := ReadConfig(configPath)
+	if err != nil {
+		log.Fatal().Msg(err.Error())
+	}
+	return cfg
+}
+
+// ReadConfig reads and parses the config file at configPath.
+func ReadConfig(configPath string) (*Config, error) {
 	if configPath == "" {
 		panic("empty configuration path")
 	}
 	log.Debug().Msg("read config file")
 	configData, err := os.ReadFile(configPath)
 	if err != nil {
-		log.Fatal().Msgf("failed to read config: %v", err)
+		return nil, errors.Wrap(err, "failed to read config")
 	}
 	log.Debug().Msg("done reading config file")
 
-	return MustParseString(configData)
+	return ParseString(configData)
 }
 
-// ParseString attempts to read and parse  config from the given string bytes.
-// An error reading or parsing the config results in a panic.
+// MustParseString attempts to read and parse config from the given string bytes.
+// An error parsing the config is fatal.
 func MustParseString(configData []byte) *Config {
+	cfg, err := ParseString(configData)
+	if err != nil {
+		log.Fatal().Msg(err.Error())
+	}
+	return cfg
+}
+
+// ParseString parses the config from the given string bytes over the defaults.
+func ParseString(configData []byte) (*Config, error) {
 	cfg := DefaultConfig()
 
 	log.Debug().Msg("parsing config data")
 	err := toml.Unmarshal(configData, &cfg)
 	if err != nil {
-		log.Fatal().Msgf("failed to decode config: %s", err)
+		return nil, errors.Wrap(err, "failed to decode config")
 	}
 	log.Debug().Msg("done parsing config data")
 
-	return &cfg
+	return &cfg, nil
 }
@@ -3,21 +3,32 @@ package evmtx
 import (
 	"encoding/json"
 	"math/big"
+	"math/rand"
 	"sync"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/pelletier/go-toml"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
 
+	"loadtester/clients"
 	"loadtester/interfaces"
+	"loadtester/metrics"
+	"loadtester/monitor"
+	"loadtester/report"
 	"loadtester/types"
 	"loadtester/utils"
 )
 
-func NewEvmTxCmd(cfg Config, ethRpc interfaces.EthRpcRequester) *cobra.Command {
+const flagTui = "tui"
+
+// NewEvmTxCmd reads the config and the client when it runs, they are loaded
+// before by the root command.
+func NewEvmTxCmd(cfg *Config, commonCfg *clients.Config, ethRpc func() interfaces.EthRpcRequester) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "evmtx",
 		Short: "Send multiple evm tx through JSON-RPC",
@@ -42,52 +53,348 @@ duration: %s`, cfg.Scenario, cfg.TransactionPerTimeUnit, cfg.TimeUnit, cfg.Durat
 			time.Sleep(3 * time.Second)
 
 			log.Info().Msgf("start load testing: scenario=%s, unit=%s, tpu=%d, duration=%s", cfg.Scenario, cfg.TimeUnit, cfg.TransactionPerTimeUnit, cfg.Duration)
-			RunScenario(&cfg, ethRpc, testAccs)
-			return nil
+			withTui, _ := cmd.Flags().GetBool(flagTui)
+			rep := RunScenario(cfg, ethRpc(), testAccs, withTui)
+			if cfg.ReportDir == "" {
+				return nil
+			}
+			return writeRunFile(*cfg, *commonCfg, rep)
 		},
 	}
+	cmd.Flags().Bool(flagTui, false, "show a live dashboard instead of the logs, with keys to pause, resume and change the rate")
 	return cmd
 }
 
-// RunScenario handles the transaction execution for a given scenario configuration.
-func RunScenario(cfg *Config, ethRpc interfaces.EthRpcRequester, testAccs []*types.Account) {
+// RunScenario handles the transaction execution for a given scenario configuration
+// and returns the report of the run. withTui shows a live dashboard while txs are sent.
+func RunScenario(cfg *Config, ethRpc interfaces.EthRpcRequester, testAccs []*types.Account, withTui bool) *report.Report {
 	senders, receivers, err := PrepareAccountsForScenario(cfg, testAccs)
-	i := 0
 	start := time.Now()
 	end := start.Add(utils.MustPareDuration(cfg.Duration))
 	timeSpentTotal := time.Duration(0)
+	var failedTotal int64
 	txHashMap := make(map[string]bool)
+	writtenTxHashMap := make(map[string]bool) // fire-and-forget txs, their acceptance is unknown
 	accMap := make(map[string]bool)
+	errCounts := report.NewErrorCounts()
+	nonceRecovery := &report.NonceRecoveryStats{}
+	stages := report.NewStageTimings()
+	latency, roundLatency := types.NewHistogram(), types.NewHistogram()
+	var intervals []types.Interval
 
 	if err != nil {
 		panic(err)
 	}
-	for {
-		startIdx := (i * cfg.TransactionPerTimeUnit) % len(senders)
-		sendersTouse := utils.SelectAccountsToUse(cfg.TransactionPerTimeUnit, senders, startIdx, "senders")
+	healthChecker, hasHealthCheck := ethRpc.(interfaces.HealthChecker)
+	if hasHealthCheck {
+		stop := healthChecker.StartHealthCheck()
+		defer stop()
+	}
+	startBlock, startBlockErr := monitor.BlockNumber(ethRpc)
+	if startBlockErr != nil {
+		log.Err(startBlockErr).Msg("failed to fetch the start block, on-chain throughput won't be measured")
+	}
+	var heads *monitor.HeadMonitor
+	var headCh <-chan uint64
+	if cfg.BlockMonitor == BlockMonitorSubscribe {
+		heads, err = startHeadMonitor(cfg.EthWsAddr)
+		if err != nil {
+			log.Err(err).Msgf("failed to subscribe to new blocks at %s, polling instead", cfg.EthWsAddr)
+		} else {
+			headCh = heads.Heads()
+		}
+	}
+	var receipts *monitor.ReceiptTracker
+	if cfg.TrackReceipts {
+		receipts = monitor.NewReceiptTracker(ethRpc, utils.MustPareDuration(cfg.ReceiptPollInterval))
+		if err := receipts.Start(headCh); err != nil {
+			log.Err(err).Msg("failed to start receipt tracking")
+			receipts = nil
+		}
+	}
+	var mempool *monitor.MempoolMonitor
+	if mempoolReporter, ok := ethRpc.(interfaces.MempoolReporter); ok && cfg.SampleMempool {
+		mempool = monitor.NewMempoolMonitor(mempoolReporter, utils.MustPareDuration(cfg.MempoolInterval))
+		mempool.Start()
+	}
+	var startStates map[common.Address]monitor.AccountState
+	if cfg.VerifyAccounts {
+		startStates = monitor.FetchAccountStates(ethRpc, senderAddrs(senders))
+	}
+	var visibility *monitor.VisibilityProber
+	if cfg.VisibilitySampleRate > 0 {
+		visibility = monitor.NewVisibilityProber(ethRpc, cfg.VisibilitySampleRate)
+	}
+	var sent *monitor.SentTxs
+	if cfg.Reconcile {
+		sent = &monitor.SentTxs{}
+	}
+	var fees *monitor.TxFees
+	if cfg.variesFees() {
+		fees = monitor.NewTxFees()
+	}
+	var exporter *metrics.Metrics
+	if cfg.MetricsAddr != "" {
+		exporter = metrics.New()
+		stop := exporter.Serve(cfg.MetricsAddr)
+		defer stop()
+		if latencyReporter, ok := ethRpc.(interfaces.LatencyReporter); ok {
+			latencyReporter.ObserveLatencies(exporter.ObserveRpc)
+		}
+	}
+	control := newRunControl(cfg.TransactionPerTimeUnit, len(senders))
+	progress := &progress{}
+	stopDashboard := func() {}
+	if withTui {
+		d := &dashboard{
+			cfg: cfg, ethRpc: ethRpc, control: control, progress: progress,
+			errors: errCounts, mempool: mempool, end: end,
+		}
+		if stop, err := d.start(); err != nil {
+			log.Err(err).Msg("failed to start the dashboard, logging instead")
+		} else {
+			stopDashboard = stop
+		}
+	}
+	startIdx := 0
+	for control.wait() {
+		tpu := control.rate()
+		if exporter != nil {
+			exporter.SetTargetRate(tpu, utils.MustPareDuration(cfg.TimeUnit))
+		}
+		sendersTouse := utils.SelectAccountsToUse(tpu, senders, startIdx, "senders")
 		if err := utils.AccSanityCheck(sendersTouse, accMap); err != nil {
 			break
 		}
-		receiversToUse := utils.SelectAccountsToUse(cfg.TransactionPerTimeUnit, receivers, startIdx, "receivers")
+		receiversToUse := utils.SelectAccountsToUse(tpu, receivers, startIdx, "receivers")
+		startIdx = (startIdx + tpu) % len(senders)
 
-		sentEthTxHashes, _, timeSpent := ExecuteEthTransactions(&TransactionContext{
-			Config:    cfg,
-			EthRpc:    ethRpc,
-			Senders:   sendersTouse,
-			Receivers: receiversToUse,
+		roundStart := time.Now()
+		sentEthTxHashes, writtenEthTxHashes, failed, timeSpent := ExecuteEthTransactions(&TransactionContext{
+			Config:        cfg,
+			EthRpc:        ethRpc,
+			Senders:       sendersTouse,
+			Receivers:     receiversToUse,
+			Errors:        errCounts,
+			NonceRecovery: nonceRecovery,
+			Stages:        stages,
+			Latency:       roundLatency,
+			Receipts:      receipts,
+			Sent:          sent,
+			Visibility:    visibility,
+			Fees:          fees,
+			Metrics:       exporter,
 		})
 		if err := utils.TxSanityCheck(sentEthTxHashes, txHashMap); err != nil {
 			break
 		}
+		if err := utils.TxSanityCheck(writtenEthTxHashes, writtenTxHashMap); err != nil {
+			break
+		}
 		UpdateMetrics(&timeSpentTotal, timeSpent)
-		if utils.TestEnded(end) {
+		failedTotal += failed
+		latency.Merge(roundLatency)
+		interval := types.Interval{
+			Start:   roundStart,
+			Sent:    len(sentEthTxHashes),
+			Failed:  failed,
+			Written: int64(len(writtenEthTxHashes)),
+			Latency: roundLatency.Summary(),
+		}
+		intervals = append(intervals, interval)
+		progress.addRound(interval, timeSpent)
+		roundLatency.Reset()
+		if utils.TestEnded(end.Add(control.pausedTotal())) {
 			break
 		}
-		i++
 	}
-	LogResults(
-		utils.MustPareDuration(cfg.TimeUnit), timeSpentTotal,
-		cfg.TransactionPerTimeUnit, len(txHashMap))
+	loadStop := time.Now()
+	loadStopBlock, loadStopBlockErr := monitor.BlockNumber(ethRpc)
+	stopDashboard()
+	if loadStopBlockErr != nil {
+		log.Err(loadStopBlockErr).Msg("failed to fetch the block at the load stop, on-chain throughput includes the blocks after it")
+	}
+	rep := &report.Report{
+		Scenario:      cfg.Scenario,
+		StartedAt:     start,
+		LoadStoppedAt: loadStop,
+		TimeUnit:      utils.MustPareDuration(cfg.TimeUnit),
+		TargetTpu:     cfg.TransactionPerTimeUnit,
+		TimeSpent:     timeSpentTotal,
+		Succeeded:     len(txHashMap),
+		Failed:        failedTotal,
+		Errors:        errCounts.Snapshot(),
+		NonceRecovery: *nonceRecovery,
+		Intervals:     intervals,
+		Latency: &types.RequestLatency{
+			Scenario: cfg.Scenario,
+			Total:    latency.Summary(),
+		},
+	}
+	if latencyReporter, ok := ethRpc.(interfaces.LatencyReporter); ok {
+		rep.Latency.Endpoints = latencyReporter.EndpointLatencies()
+	}
+	if hasHealthCheck {
+		rep.Failovers = healthChecker.FailoverEvents()
+	}
+	if retryReporter, ok := ethRpc.(interfaces.RetryReporter); ok {
+		rep.Retries = retryReporter.RetryStats()
+	}
+	if fafReporter, ok := ethRpc.(interfaces.FireAndForgetReporter); ok {
+		if stats, enabled := fafReporter.FireAndForgetStats(); enabled {
+			rep.FireAndForget = &stats
+		}
+	}
+	if receipts != nil {
+		log.Info().Msgf("waiting up to %s for pending txs to be included", cfg.ReceiptTimeout)
+		inclusion := receipts.Finish(utils.MustPareDuration(cfg.ReceiptTimeout))
+		rep.Inclusion = &inclusion
+	}
+	rep.Stages = stages.Summaries()
+	if visibility != nil {
+		latency, invisible := visibility.Finish()
+		if invisible > 0 {
+			log.Warn().Msgf("%d sampled txs never became visible through eth_getTransactionByHash", invisible)
+		}
+		if latency.Count > 0 {
+			rep.Stages[types.StageMempoolVisibility] = latency
+		}
+	}
+	if rep.Inclusion != nil && rep.Inclusion.Latency.Count > 0 {
+		rep.Stages[types.StageInclusion] = rep.Inclusion.Latency
+	}
+	if heads != nil {
+		rep.Blocks = heads.Stop()
+	}
+	if mempool != nil {
+		rep.Mempool = mempool.Stop()
+	}
+	if startStates != nil {
+		log.Info().Msg("verifying the on-chain nonce and balance of the senders")
+		verification := monitor.VerifyAccounts(ethRpc, senders, startStates, txCost(cfg))
+		rep.Accounts = &verification
+	}
+	if sent != nil {
+		log.Info().Msg("reconciling accepted txs against the chain state")
+		reconciliation := monitor.Reconcile(ethRpc, sent.All())
+		rep.Reconciliation = &reconciliation
+	}
+	if startBlockErr == nil {
+		if blocks := fetchRunBlocks(ethRpc, startBlock); blocks != nil {
+			ours := make(map[string]bool, len(txHashMap)+len(writtenTxHashMap))
+			for _, m := range []map[string]bool{txHashMap, writtenTxHashMap} {
+				for hash := range m {
+					ours[hash] = true
+				}
+			}
+			// the later blocks are only fetched for the catch-up and the series
+			loadBlocks := blocks
+			if loadStopBlockErr == nil && loadStopBlock >= startBlock && int(loadStopBlock-startBlock) < len(blocks) {
+				loadBlocks = blocks[:loadStopBlock-startBlock+1]
+			}
+			throughput := monitor.Throughput(loadBlocks, ours)
+			health := monitor.BlockHealth(blocks, loadStop, ours)
+			rep.Throughput, rep.BlockHealth = &throughput, &health
+			rep.BlockSeries = monitor.BlockSeries(blocks)
+			if fees != nil {
+				ordering := monitor.FeeOrdering(blocks, fees)
+				rep.FeeOrdering = &ordering
+			}
+		}
+	}
+	rep.EndedAt = time.Now()
+	LogResults(rep)
+	return rep
+}
+
+// writeRunFile writes the report with the effective config into the report dir.
+func writeRunFile(cfg Config, commonCfg clients.Config, rep *report.Report) error {
+	effective, err := effectiveConfig(cfg, commonCfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode the effective config")
+	}
+	paths, err := report.WriteRunFile(cfg.ReportDir, report.RunFile{
+		Version: report.ToolVersion(),
+		Command: "evmtx",
+		Config:  effective,
+		Report:  rep,
+	}, cfg.ReportCsv)
+	if err != nil {
+		return errors.Wrap(err, "failed to write the run file")
+	}
+	for _, path := range paths {
+		log.Info().Msgf("wrote %s", path)
+	}
+	return nil
+}
+
+// effectiveConfig returns the config of the run keyed like config.toml, with
+// credentials redacted.
+func effectiveConfig(cfg Config, commonCfg clients.Config) (map[string]interface{}, error) {
+	cfg.EthWsAddr = clients.RedactURL(cfg.EthWsAddr)
+	b, err := toml.Marshal(struct {
+		Common clients.Config `toml:"common"`
+		EvmTx  Config         `toml:"evmtx"`
+	}{commonCfg.Redacted(), cfg})
+	if err != nil {
+		return nil, err
+	}
+	tree, err := toml.LoadBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	return tree.ToMap(), nil
+}
+
+func senderAddrs(senders []*types.Account) []common.Address {
+	addrs := make([]common.Address, 0, len(senders))
+	for _, sender := range senders {
+		addrs = append(addrs, sender.EthAddr)
+	}
+	return addrs
+}
+
+// txCost returns what each tx of the scenario costs its sender.
+func txCost(cfg *Config) monitor.TxCost {
+	value := big.NewInt(cfg.SendingAmt)
+	if cfg.Scenario == ScenarioEthTransferToSelf {
+		value = new(big.Int)
+	}
+	cost := monitor.TxCost{Value: value, GasLimit: uint64(cfg.GasLimit), GasPrice: big.NewInt(cfg.GasPrice)}
+	if cfg.variesFees() {
+		cost.MaxGasPrice = big.NewInt(cfg.maxGasPrice())
+	}
+	return cost
+}
+
+func startHeadMonitor(wsAddr string) (*monitor.HeadMonitor, error) {
+	heads, err := monitor.NewHeadMonitor(wsAddr)
+	if err != nil {
+		return nil, err
+	}
+	if err := heads.Start(); err != nil {
+		return nil, err
+	}
+	return heads, nil
+}
+
+// fetchRunBlocks returns the blocks from startBlock to the current head, nil
+// if they can't be fetched or no block was produced.
+func fetchRunBlocks(ethRpc interfaces.EthRpcRequester, startBlock uint64) []types.Block {
+	endBlock, err := monitor.BlockNumber(ethRpc)
+	if err != nil {
+		log.Err(err).Msg("failed to fetch the end block")
+		return nil
+	}
+	if endBlock <= startBlock {
+		return nil
+	}
+	blocks, err := monitor.FetchBlocks(ethRpc, startBlock, endBlock)
+	if err != nil {
+		log.Err(err).Msg("failed to fetch the blocks of the run")
+		return nil
+	}
+	return blocks
 }
 
 // Prepares senders and receivers based on the test scenario.
@@ -106,8 +413,10 @@ func PrepareAccountsForScenario(cfg *Config, testAccs []*types.Account) (senders
 	}
 }
 
-// ExecuteEthTransactions executes the transactions for the given context.
-func ExecuteEthTransactions(ctx *TransactionContext) ([]string, int64, time.Duration) {
+// ExecuteEthTransactions executes the transactions for the given context. It
+// returns the hashes of the txs accepted by the node, of the txs only written
+// in fire-and-forget mode, and how many txs failed.
+func ExecuteEthTransactions(ctx *TransactionContext) (sent, written []string, failed int64, timeSpent time.Duration) {
 	signingStart := time.Now()
 	log.Debug().Msgf("signing %d transactions", len(ctx.Senders))
 	wg := sync.WaitGroup{}
@@ -117,17 +426,56 @@ func ExecuteEthTransactions(ctx *TransactionContext) ([]string, int64, time.Dura
 	sendingStart := time.Now()
 	log.Debug().Msgf("sending %d transactions", len(reqBodies))
 
-	var sentEthTxHashes []string
-	failed := ctx.EthRpc.EthSendMultipleRawTransactions(reqBodies, func(mu *sync.Mutex, idx int) {
+	var sentEthTxHashes, writtenEthTxHashes []string
+	if ctx.Metrics != nil {
+		ctx.Metrics.Sending(len(reqBodies))
+	}
+	ctx.EthRpc.EthSendMultipleRawTransactions(reqBodies, func(mu *sync.Mutex, idx int, timing types.SendTiming, err error) {
+		if ctx.Metrics != nil {
+			ctx.Metrics.Sent(timing, err)
+		}
+		ctx.Stages.Add(types.StageQueueing, timing.Start.Sub(sendingStart))
+		ctx.Stages.Add(types.StageSend, timing.End.Sub(timing.Start))
+		mu.Lock()
+		ctx.Latency.Record(timing.End.Sub(timing.Start))
+		mu.Unlock()
+		txHash := txHashes[idx]
+		if err != nil {
+			ctx.Errors.Add(err)
+			if txHash, err = RecoverNonce(ctx, idx, err); err != nil {
+				return
+			}
+		}
+		// a written tx may still be rejected by the node, which receipts and
+		// reconciliation tell, the sender isn't reused within the run anyway
+		if ctx.Sent != nil {
+			ctx.Sent.Add(txHash, *ctx.Senders[idx].GetEthAddr(), ctx.Senders[idx].GetNonce())
+		}
 		ctx.Senders[idx].IncreaseNonce() // off-chain nonce increment for faster processing
+		if ctx.Receipts != nil {
+			ctx.Receipts.Track(txHash, timing.Start)
+		}
+		if ctx.Visibility != nil {
+			ctx.Visibility.Probe(txHash, timing.Start)
+		}
+		if timing.Written {
+			mu.Lock()
+			writtenEthTxHashes = append(writtenEthTxHashes, txHash)
+			mu.Unlock()
+			return
+		}
+		if ctx.Metrics != nil {
+			ctx.Metrics.Succeeded()
+		}
 		mu.Lock()
-		sentEthTxHashes = append(sentEthTxHashes, txHashes[idx])
+		sentEthTxHashes = append(sentEthTxHashes, txHash)
 		mu.Unlock()
 	})
 
 	timeSpentForSending := time.Since(sendingStart)
-	succeeded := int64(len(reqBodies)) - failed
-	log.Debug().Msgf("done sending. succeeded: %d, failed: %d, took %s", succeeded, failed, timeSpentForSending.String())
+	failed = int64(len(reqBodies) - len(sentEthTxHashes) - len(writtenEthTxHashes)) // recovered txs are not failures
+	log.Debug().Msgf("done sending. succeeded: %d, written: %d, failed: %d, took %s",
+		len(sentEthTxHashes), len(writtenEthTxHashes), failed, timeSpentForSending.String())
 
 	timeUnit := utils.MustPareDuration(ctx.Config.TimeUnit)
 	if timeSpentForSending < timeUnit {
@@ -137,17 +485,13 @@ func ExecuteEthTransactions(ctx *TransactionContext) ([]string, int64, time.Dura
 		timeSpentForSending = timeUnit
 	}
 
-	return sentEthTxHashes, failed, timeSpentForSending
+	return sentEthTxHashes, writtenEthTxHashes, failed, timeSpentForSending
 }
 
 // CreateEthSendRawTransactionReqBodies creates eth_sendRawTransaction request bodies with go routines
 func CreateEthSendRawTransactionReqBodies(
 	ctx *TransactionContext, wg *sync.WaitGroup,
 ) (reqBodies [][]byte, txHashes []string) {
-	gasLimit := uint64(ctx.Config.GasLimit)
-	gasPrice := big.NewInt(ctx.Config.GasPrice)
-	val := new(big.Int).SetInt64(ctx.Config.SendingAmt)
-
 	reqBodies = make([][]byte, len(ctx.Senders))
 	txHashes = make([]string, len(ctx.Senders))
 
@@ -155,47 +499,77 @@ func CreateEthSendRawTransactionReqBodies(
 		wg.Add(1)
 		go func(w *sync.WaitGroup, idx int) {
 			defer w.Done()
-			// prepare legacy tx
-			unsignedTx := gethtypes.NewTx(&gethtypes.LegacyTx{
-				To:       ctx.Receivers[idx].GetEthAddr(),
-				Nonce:    ctx.Senders[idx].GetNonce(),
-				Value:    val,
-				Gas:      gasLimit,
-				GasPrice: gasPrice,
-			})
-			signer := gethtypes.NewEIP155Signer(big.NewInt(ctx.Config.ChainID))
-			signedTx, _ := gethtypes.SignTx(unsignedTx, signer, ctx.Senders[idx].GetEthPrivKey())
-			marshaled, _ := signedTx.MarshalBinary()
-			reqBody, err := json.Marshal(map[string]interface{}{
-				"jsonrpc": "2.0",
-				"method":  "eth_sendRawTransaction",
-				"params":  []string{hexutil.Encode(marshaled)},
-				"id":      1,
-			})
+			signingStart := time.Now()
+			reqBody, txHash, err := SignEthSendRawTransaction(ctx, idx)
+			signing := time.Since(signingStart)
+			ctx.Stages.Add(types.StageSigning, signing)
+			if ctx.Metrics != nil {
+				ctx.Metrics.Signed(signing)
+			}
 			if err != nil {
 				log.Err(err).Msg("Failed to marshal request body")
 				return
 			}
 			reqBodies[idx] = reqBody
-			txHashes[idx] = signedTx.Hash().Hex()
+			txHashes[idx] = txHash
 		}(wg, i)
 	}
 	wg.Wait()
 	return
 }
 
-func LogResults(timeUnit, timeSpentTotal time.Duration, targetTpu, succeeded int) {
-	totalSent := float64(succeeded)
-	var tpu float64
-	switch timeUnit {
-	case time.Millisecond:
-		tpu = totalSent / float64(timeSpentTotal.Milliseconds())
-	case time.Second:
-		tpu = totalSent / timeSpentTotal.Seconds()
+// SignEthSendRawTransaction signs the tx of the idx-th sender with its current
+// nonce and wraps it into an eth_sendRawTransaction request body.
+func SignEthSendRawTransaction(ctx *TransactionContext, idx int) (reqBody []byte, txHash string, err error) {
+	gasPrice := ctx.Config.GasPrice
+	if ctx.Config.variesFees() {
+		gasPrice += rand.Int63n(int64(ctx.Config.FeeTiers)) * ctx.Config.FeeTierStep
+	}
+	// prepare legacy tx
+	unsignedTx := gethtypes.NewTx(&gethtypes.LegacyTx{
+		To:       ctx.Receivers[idx].GetEthAddr(),
+		Nonce:    ctx.Senders[idx].GetNonce(),
+		Value:    new(big.Int).SetInt64(ctx.Config.SendingAmt),
+		Gas:      uint64(ctx.Config.GasLimit),
+		GasPrice: big.NewInt(gasPrice),
+	})
+	signer := gethtypes.NewEIP155Signer(big.NewInt(ctx.Config.ChainID))
+	signedTx, _ := gethtypes.SignTx(unsignedTx, signer, ctx.Senders[idx].GetEthPrivKey())
+	marshaled, _ := signedTx.MarshalBinary()
+	reqBody, err = json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "eth_sendRawTransaction",
+		"params":  []string{hexutil.Encode(marshaled)},
+		"id":      1,
+	})
+	if err != nil {
+		return nil, "", err
 	}
+	if ctx.Fees != nil {
+		ctx.Fees.Add(signedTx.Hash().Hex(), *ctx.Senders[idx].GetEthAddr(), gasPrice, time.Now())
+	}
+	return reqBody, signedTx.Hash().Hex(), nil
+}
+
+func LogResults(rep *report.Report) {
 	log.Info().Msgf(
-		"evmtx load testing finished, numTotalSent:%v, timeSpent:%v, timeUnit:%s, targetTpu:%d, realTpu:%.2f",
-		totalSent, timeSpentTotal, timeUnit, targetTpu, tpu)
+		"evmtx load testing finished, numTotalSent:%v, numFailed:%d, timeSpent:%v, timeUnit:%s, targetTpu:%d, clientTpu:%.2f",
+		rep.Succeeded, rep.Failed, rep.TimeSpent, rep.TimeUnit, rep.TargetTpu, rep.Tpu())
+	rep.LogLatency()
+	rep.LogErrors()
+	rep.LogNonceRecovery()
+	rep.LogRetries()
+	rep.LogFireAndForget()
+	rep.LogFailovers()
+	rep.LogStages()
+	rep.LogInclusion()
+	rep.LogThroughput()
+	rep.LogBlockHealth()
+	rep.LogFeeOrdering()
+	rep.LogBlocks()
+	rep.LogMempool()
+	rep.LogReconciliation()
+	rep.LogAccounts()
 }
 
 func UpdateMetrics(timeSpentTotal *time.Duration, timeSpent time.Duration) {
@@ -1,15 +1,31 @@
 package evmtx
 
 const (
-	DefaultGasLimit     = 200000
-	DefaultGasPrice     = 201417240
-	DefaultSendingAmt   = 1
-	DefaultChainId      = 1124124
-	DefaultDuration     = "10m"
-	DefaultTps          = 10
-	DefaultAccNum       = 100
-	DefaultValidatorNum = 1
-	DefaultScenario     = ScenarioEthTransferToRandom
+	DefaultGasLimit       = 200000
+	DefaultGasPrice       = 201417240
+	DefaultSendingAmt     = 1
+	DefaultChainId        = 1124124
+	DefaultDuration       = "10m"
+	DefaultTps            = 10
+	DefaultAccNum         = 100
+	DefaultValidatorNum   = 1
+	DefaultScenario       = ScenarioEthTransferToRandom
+	DefaultNonceRecovery  = NonceRecoveryResync
+	DefaultTrackReceipts  = false
+	DefaultReceiptPoll    = "200ms"
+	DefaultReceiptWait    = "30s"
+	DefaultBlockMonitor   = BlockMonitorPoll
+	DefaultEthWsAddr      = "ws://localhost:8546"
+	DefaultReconcile      = false
+	DefaultSampleMempool  = false
+	DefaultMempoolPoll    = "1s"
+	DefaultVisibilityRate = 0
+	DefaultVerifyAccounts = false
+	DefaultFeeTiers       = 1
+	DefaultFeeTierStep    = 100000000
+	DefaultReportDir      = "reports"
+	DefaultReportCsv      = false
+	DefaultMetricsAddr    = ""
 )
 
 const (
@@ -23,6 +39,20 @@ const (
 	//ScenarioErc20Transfer = "erc20_transfer"
 )
 
+const (
+	// only count nonce errors, the sender keeps its wrong nonce
+	NonceRecoveryOff = "off"
+	// resync the nonce of the sender, then re-sign and resubmit the tx once
+	NonceRecoveryResync = "resync"
+)
+
+const (
+	// poll the head of the chain for new blocks
+	BlockMonitorPoll = "poll"
+	// subscribe to newHeads over websocket, timestamping each block on arrival
+	BlockMonitorSubscribe = "subscribe"
+)
+
 type Config struct {
 	GasLimit               int64  `toml:"gas_limit"`
 	GasPrice               int64  `toml:"gas_price"`
@@ -33,6 +63,37 @@ type Config struct {
 	TimeUnit               string `toml:"time_unit"`
 	AccNum                 int    `toml:"acc_num"`
 	Scenario               string `toml:"scenario"`
+	NonceRecovery          string `toml:"nonce_recovery"`
+	// TrackReceipts watches new blocks for the sent txs to measure inclusion
+	TrackReceipts       bool   `toml:"track_receipts"`
+	ReceiptPollInterval string `toml:"receipt_poll_interval"`
+	// ReceiptTimeout is how long to wait for pending txs once sending stops
+	ReceiptTimeout string `toml:"receipt_timeout"`
+	BlockMonitor   string `toml:"block_monitor"`
+	EthWsAddr      string `toml:"eth_ws_addr"`
+	// Reconcile classifies every accepted tx against the chain state after the run
+	Reconcile bool `toml:"reconcile"`
+	// SampleMempool records the mempool occupancy every MempoolInterval
+	SampleMempool   bool   `toml:"sample_mempool"`
+	MempoolInterval string `toml:"mempool_interval"`
+	// VisibilitySampleRate is the fraction of accepted txs polled with
+	// eth_getTransactionByHash to measure mempool visibility, 0 disables it
+	VisibilitySampleRate float64 `toml:"visibility_sample_rate"`
+	// VerifyAccounts compares the on-chain nonce and balance of every sender
+	// with the local state after the run
+	VerifyAccounts bool `toml:"verify_accounts"`
+	// FeeTiers spreads the gas price of the txs over FeeTiers tiers, tier i
+	// paying GasPrice + i*FeeTierStep, to measure whether inclusion order
+	// respects fee priority. 1 sends every tx at GasPrice
+	FeeTiers    int   `toml:"fee_tiers"`
+	FeeTierStep int64 `toml:"fee_tier_step"`
+	// ReportDir is where the json run file is written, empty disables it
+	ReportDir string `toml:"report_dir"`
+	// ReportCsv also writes the time series of the run as csv files
+	ReportCsv bool `toml:"report_csv"`
+	// MetricsAddr serves prometheus metrics at /metrics during the run, empty
+	// disables it
+	MetricsAddr string `toml:"metrics_addr"`
 }
 
 func DefaultConfig() Config {
@@ -45,5 +106,31 @@ func DefaultConfig() Config {
 		TransactionPerTimeUnit: DefaultTps,
 		AccNum:                 DefaultAccNum,
 		Scenario:               DefaultScenario,
+		NonceRecovery:          DefaultNonceRecovery,
+		TrackReceipts:          DefaultTrackReceipts,
+		ReceiptPollInterval:    DefaultReceiptPoll,
+		ReceiptTimeout:         DefaultReceiptWait,
+		BlockMonitor:           DefaultBlockMonitor,
+		EthWsAddr:              DefaultEthWsAddr,
+		Reconcile:              DefaultReconcile,
+		SampleMempool:          DefaultSampleMempool,
+		MempoolInterval:        DefaultMempoolPoll,
+		VisibilitySampleRate:   DefaultVisibilityRate,
+		VerifyAccounts:         DefaultVerifyAccounts,
+		FeeTiers:               DefaultFeeTiers,
+		FeeTierStep:            DefaultFeeTierStep,
+		ReportDir:              DefaultReportDir,
+		ReportCsv:              DefaultReportCsv,
+		MetricsAddr:            DefaultMetricsAddr,
 	}
 }
+
+// variesFees tells whether the gas price of the txs is spread over tiers.
+func (cfg *Config) variesFees() bool {
+	return cfg.FeeTiers > 1
+}
+
+// maxGasPrice returns the gas price of the highest fee tier.
+func (cfg *Config) maxGasPrice() int64 {
+	return cfg.GasPrice + int64(max(cfg.FeeTiers, 1)-1)*cfg.FeeTierStep
+}
@@ -0,0 +1,220 @@
+package evmtx
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"loadtester/interfaces"
+	"loadtester/monitor"
+	"loadtester/report"
+	"loadtester/tui"
+	"loadtester/types"
+	"loadtester/utils"
+)
+
+const (
+	// dashboardRounds is how many rounds the latency sparkline spans
+	dashboardRounds = 60
+	dashboardLogs   = 8
+)
+
+// runControl lets the dashboard pause, resume, stop and change the rate of a
+// running scenario. It is safe for concurrent use.
+type runControl struct {
+	mu        sync.Mutex
+	tpu       int
+	maxTpu    int
+	paused    bool
+	pausedAt  time.Time
+	pausedFor time.Duration
+	stopped   bool
+	resume    chan struct{}
+}
+
+func newRunControl(tpu, maxTpu int) *runControl {
+	return &runControl{tpu: tpu, maxTpu: maxTpu, resume: make(chan struct{})}
+}
+
+func (rc *runControl) TogglePause() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if !rc.paused {
+		rc.paused, rc.pausedAt = true, time.Now()
+		return
+	}
+	rc.paused = false
+	rc.pausedFor += time.Since(rc.pausedAt)
+	close(rc.resume)
+	rc.resume = make(chan struct{})
+}
+
+// ScaleRate changes the txs per time unit of the next rounds, keeping at least
+// one tx and at most one tx per sender.
+func (rc *runControl) ScaleRate(factor float64) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	tpu := int(float64(rc.tpu)*factor + 0.5)
+	if tpu == rc.tpu && factor > 1 {
+		tpu++
+	} else if tpu == rc.tpu && factor < 1 {
+		tpu--
+	}
+	rc.tpu = min(max(tpu, 1), rc.maxTpu)
+}
+
+func (rc *runControl) Stop() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.stopped = true
+	if rc.paused {
+		rc.paused = false
+		rc.pausedFor += time.Since(rc.pausedAt)
+		close(rc.resume)
+		rc.resume = make(chan struct{})
+	}
+}
+
+// wait blocks while the run is paused and tells whether it should go on.
+func (rc *runControl) wait() bool {
+	rc.mu.Lock()
+	for rc.paused {
+		resume := rc.resume
+		rc.mu.Unlock()
+		<-resume
+		rc.mu.Lock()
+	}
+	defer rc.mu.Unlock()
+	return !rc.stopped
+}
+
+func (rc *runControl) rate() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.tpu
+}
+
+// pausedTotal returns how long the run was paused, the current pause included.
+func (rc *runControl) pausedTotal() time.Duration {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.paused {
+		return rc.pausedFor + time.Since(rc.pausedAt)
+	}
+	return rc.pausedFor
+}
+
+func (rc *runControl) isPaused() bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.paused
+}
+
+// progress is what the dashboard shows of the finished rounds.
+type progress struct {
+	mu        sync.Mutex
+	succeeded int64
+	written   int64
+	failed    int64
+	rate      float64
+	latencies []time.Duration
+	block     uint64
+}
+
+func (p *progress) addRound(interval types.Interval, timeSpent time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.succeeded += int64(interval.Sent)
+	p.written += interval.Written
+	p.failed += interval.Failed
+	p.rate = float64(int64(interval.Sent)+interval.Written) / timeSpent.Seconds()
+	if interval.Latency.Count > 0 {
+		p.latencies = append(p.latencies, interval.Latency.P99)
+		if len(p.latencies) > dashboardRounds {
+			p.latencies = p.latencies[1:]
+		}
+	}
+}
+
+func (p *progress) setBlock(block uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.block = block
+}
+
+// dashboard is the state the live dashboard of a run is drawn from.
+type dashboard struct {
+	cfg      *Config
+	ethRpc   interfaces.EthRpcRequester
+	control  *runControl
+	progress *progress
+	errors   *report.ErrorCounts
+	mempool  *monitor.MempoolMonitor
+	end      time.Time // before pauses
+	logs     *tui.LogBuffer
+}
+
+// start replaces the console logs with the dashboard until the returned stop
+// is called. Warnings and errors are shown inside the dashboard.
+func (d *dashboard) start() (stop func(), err error) {
+	d.logs = tui.NewLogBuffer(dashboardLogs)
+	releaseLogs := tui.Logs.Capture(zerolog.ConsoleWriter{Out: d.logs, NoColor: true, TimeFormat: "15:04:05"}, zerolog.WarnLevel)
+	stopDashboard, err := tui.Start(os.Stdin, os.Stdout, d.snapshot, d.control)
+	if err != nil {
+		releaseLogs()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	go d.pollHead(done)
+	return func() {
+		close(done)
+		stopDashboard()
+		releaseLogs()
+	}, nil
+}
+
+// pollHead keeps the latest block height fresh for the dashboard.
+func (d *dashboard) pollHead(done <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		if head, err := monitor.BlockNumber(d.ethRpc); err == nil {
+			d.progress.setBlock(head)
+		}
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *dashboard) snapshot() tui.Snapshot {
+	timeUnit := utils.MustPareDuration(d.cfg.TimeUnit)
+	s := tui.Snapshot{
+		Title:      "loadtester evmtx: " + d.cfg.Scenario,
+		Paused:     d.control.isPaused(),
+		TargetRate: float64(d.control.rate()) / timeUnit.Seconds(),
+		Remaining:  max(time.Until(d.end.Add(d.control.pausedTotal())), 0),
+		Errors:     make(map[string]int64),
+		Logs:       d.logs.Lines(),
+	}
+	for category, count := range d.errors.Snapshot() {
+		s.Errors[string(category)] = count
+	}
+	if d.mempool != nil {
+		if sample, ok := d.mempool.Latest(); ok {
+			s.HasMempool, s.Mempool = true, sample.Pending+sample.Queued
+		}
+	}
+	d.progress.mu.Lock()
+	defer d.progress.mu.Unlock()
+	s.Succeeded, s.Written, s.Failed = d.progress.succeeded, d.progress.written, d.progress.failed
+	s.CurrentRate = d.progress.rate
+	s.Latencies = append([]time.Duration(nil), d.progress.latencies...)
+	s.Block = d.progress.block
+	return s
+}
@@ -0,0 +1,81 @@
+package evmtx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"loadtester/report"
+	"loadtester/tui"
+)
+
+func TestRunControlPause(t *testing.T) {
+	rc := newRunControl(10, 100)
+	require.True(t, rc.wait())
+
+	rc.TogglePause()
+	require.True(t, rc.isPaused())
+	resumed := make(chan bool)
+	go func() { resumed <- rc.wait() }()
+	select {
+	case <-resumed:
+		t.Fatal("wait returned while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.GreaterOrEqual(t, rc.pausedTotal(), 50*time.Millisecond)
+
+	rc.TogglePause()
+	require.True(t, <-resumed)
+	require.False(t, rc.isPaused())
+	paused := rc.pausedTotal()
+	require.GreaterOrEqual(t, paused, 50*time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	require.Equal(t, paused, rc.pausedTotal(), "only pauses count")
+}
+
+func TestRunControlStopWhilePaused(t *testing.T) {
+	rc := newRunControl(10, 100)
+	rc.TogglePause()
+	stopped := make(chan bool)
+	go func() { stopped <- rc.wait() }()
+	rc.Stop()
+	require.False(t, <-stopped)
+	require.False(t, rc.isPaused())
+	require.False(t, rc.wait())
+}
+
+func TestRunControlScaleRate(t *testing.T) {
+	rc := newRunControl(10, 12)
+	for _, want := range []int{11, 12, 12} {
+		rc.ScaleRate(1.1)
+		require.Equal(t, want, rc.rate())
+	}
+
+	rc = newRunControl(2, 12)
+	for _, want := range []int{1, 1} {
+		rc.ScaleRate(0.9)
+		require.Equal(t, want, rc.rate())
+	}
+	// rounding would keep small rates as they are
+	rc.ScaleRate(1.1)
+	require.Equal(t, 2, rc.rate())
+}
+
+func TestDashboardRemainingExtendedByPauses(t *testing.T) {
+	d := &dashboard{
+		cfg:      &Config{Scenario: "transfer", TimeUnit: "1s"},
+		control:  newRunControl(10, 100),
+		progress: &progress{},
+		errors:   report.NewErrorCounts(),
+		logs:     tui.NewLogBuffer(dashboardLogs),
+		end:      time.Now().Add(time.Second),
+	}
+	d.control.TogglePause()
+	time.Sleep(200 * time.Millisecond)
+	// the remaining time doesn't run down while paused
+	require.Greater(t, d.snapshot().Remaining, 900*time.Millisecond)
+	d.control.TogglePause()
+	require.Greater(t, d.snapshot().Remaining, 900*time.Millisecond)
+	require.Equal(t, float64(10), d.snapshot().TargetRate)
+}
@@ -0,0 +1,62 @@
+package evmtx
+
+import (
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"loadtester/interfaces"
+	"loadtester/types"
+)
+
+// RecoverNonce handles a failed submission of the idx-th sender. On a nonce
+// error, unless recovery is off, the sender's nonce is resynced and the tx
+// re-signed and resubmitted once. It returns the hash of the resubmitted tx,
+// or an error if the tx stays failed.
+func RecoverNonce(ctx *TransactionContext, idx int, sendErr error) (string, error) {
+	var nonceErr *types.NonceError
+	if !errors.As(sendErr, &nonceErr) || ctx.Config.NonceRecovery == NonceRecoveryOff {
+		return "", sendErr
+	}
+	sender := ctx.Senders[idx]
+	nonce, err := resyncNonce(ctx, sender, nonceErr)
+	if err != nil {
+		return "", err
+	}
+	sender.SetNonce(nonce)
+	atomic.AddInt64(&ctx.NonceRecovery.Resyncs, 1)
+
+	reqBody, txHash, err := SignEthSendRawTransaction(ctx, idx)
+	if err != nil {
+		return "", err
+	}
+	// the first attempt already went through the retry policy
+	send := ctx.EthRpc.EthSendRawTransaction
+	if once, ok := ctx.EthRpc.(interfaces.SingleAttemptSender); ok {
+		send = once.EthSendRawTransactionOnce
+	}
+	if err := send(reqBody); err != nil {
+		atomic.AddInt64(&ctx.NonceRecovery.ResubmitFailed, 1)
+		log.Debug().Err(err).Msgf("failed to resubmit the tx of %s", sender.GetEthAddr().Hex())
+		return "", err
+	}
+	atomic.AddInt64(&ctx.NonceRecovery.Resubmitted, 1)
+	return txHash, nil
+}
+
+// resyncNonce returns the nonce the node expects, taken from the error message
+// if it tells one and queried from the node otherwise. The query counts the
+// txs of the sender still in the mempool, which the latest block does not.
+func resyncNonce(ctx *TransactionContext, sender *types.Account, nonceErr *types.NonceError) (uint64, error) {
+	if nonceErr.HasNonce {
+		return nonceErr.Nonce, nil
+	}
+	atomic.AddInt64(&ctx.NonceRecovery.Queried, 1)
+	var nonce hexutil.Uint64
+	if err := ctx.EthRpc.RpcCall(&nonce, "eth_getTransactionCount", sender.GetEthAddr(), "pending"); err != nil {
+		return 0, errors.Wrap(types.ErrorFailedToFetchNonce, err.Error())
+	}
+	return uint64(nonce), nil
+}
@@ -0,0 +1,141 @@
+package evmtx
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"loadtester/report"
+	"loadtester/types"
+)
+
+// fakeRequester records the resubmissions and answers nonce queries with
+// pendingNonce.
+type fakeRequester struct {
+	pendingNonce uint64
+	sendErr      error
+
+	retried [][]byte
+	once    [][]byte
+	queries [][]interface{}
+}
+
+func (f *fakeRequester) EthSendRawTransaction(rawTx []byte) error {
+	f.retried = append(f.retried, rawTx)
+	return f.sendErr
+}
+
+func (f *fakeRequester) EthSendRawTransactionOnce(rawTx []byte) error {
+	f.once = append(f.once, rawTx)
+	return f.sendErr
+}
+
+func (f *fakeRequester) EthSendRawTransactionNoWaiting(rawTx []byte) error {
+	return nil
+}
+
+func (f *fakeRequester) EthPendingNonce(addr common.Address) (uint64, error) {
+	return 0, errors.New("the resync must not query the latest nonce")
+}
+
+func (f *fakeRequester) EthSendMultipleRawTransactions(rawTxs [][]byte, cb func(*sync.Mutex, int, types.SendTiming, error)) int64 {
+	return 0
+}
+
+func (f *fakeRequester) RpcCall(result interface{}, method string, params ...interface{}) error {
+	f.queries = append(f.queries, append([]interface{}{method}, params...))
+	*result.(*hexutil.Uint64) = hexutil.Uint64(f.pendingNonce)
+	return nil
+}
+
+func newRecoveryContext(t *testing.T, rpc *fakeRequester, mode string) *TransactionContext {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := types.NewAccount(key)
+	sender.SetNonce(3)
+	cfg := DefaultConfig()
+	cfg.NonceRecovery = mode
+	return &TransactionContext{
+		Config:        &cfg,
+		EthRpc:        rpc,
+		Senders:       []*types.Account{sender},
+		Receivers:     []*types.Account{sender},
+		NonceRecovery: &report.NonceRecoveryStats{},
+	}
+}
+
+func sentNonce(t *testing.T, reqBody []byte) (uint64, string) {
+	var req struct {
+		Params []string `json:"params"`
+	}
+	require.NoError(t, json.Unmarshal(reqBody, &req))
+	raw, err := hexutil.Decode(req.Params[0])
+	require.NoError(t, err)
+	tx := new(gethtypes.Transaction)
+	require.NoError(t, tx.UnmarshalBinary(raw))
+	return tx.Nonce(), tx.Hash().Hex()
+}
+
+func TestRecoverNonceResync(t *testing.T) {
+	rpc := &fakeRequester{}
+	ctx := newRecoveryContext(t, rpc, NonceRecoveryResync)
+
+	// the expected nonce is taken from the error message
+	txHash, err := RecoverNonce(ctx, 0, &types.NonceError{Nonce: 5, HasNonce: true})
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), ctx.Senders[0].GetNonce())
+	require.Empty(t, rpc.retried, "the resubmission bypasses the retry policy")
+	require.Len(t, rpc.once, 1)
+	nonce, hash := sentNonce(t, rpc.once[0])
+	require.Equal(t, uint64(5), nonce)
+	require.Equal(t, hash, txHash)
+	require.Empty(t, rpc.queries)
+
+	// or queried from the pending state of the node
+	rpc.pendingNonce = 9
+	_, err = RecoverNonce(ctx, 0, &types.NonceError{Message: "invalid nonce"})
+	require.NoError(t, err)
+	require.Equal(t, [][]interface{}{{"eth_getTransactionCount", ctx.Senders[0].GetEthAddr(), "pending"}}, rpc.queries)
+	nonce, _ = sentNonce(t, rpc.once[1])
+	require.Equal(t, uint64(9), nonce)
+
+	require.Equal(t, report.NonceRecoveryStats{Resyncs: 2, Queried: 1, Resubmitted: 2}, *ctx.NonceRecovery)
+}
+
+func TestRecoverNonceResubmitFailed(t *testing.T) {
+	underpriced := &types.RpcError{Message: "transaction underpriced", Category: types.CategoryUnderpriced}
+	rpc := &fakeRequester{sendErr: underpriced}
+	ctx := newRecoveryContext(t, rpc, NonceRecoveryResync)
+
+	_, err := RecoverNonce(ctx, 0, &types.NonceError{Nonce: 5, HasNonce: true})
+	require.ErrorIs(t, err, underpriced)
+	require.Equal(t, report.NonceRecoveryStats{Resyncs: 1, ResubmitFailed: 1}, *ctx.NonceRecovery)
+}
+
+func TestRecoverNonceSkipped(t *testing.T) {
+	rpc := &fakeRequester{}
+	nonceErr := &types.NonceError{Nonce: 5, HasNonce: true}
+
+	// recovery is off
+	ctx := newRecoveryContext(t, rpc, NonceRecoveryOff)
+	_, err := RecoverNonce(ctx, 0, nonceErr)
+	require.ErrorIs(t, err, nonceErr)
+
+	// not a nonce error
+	ctx = newRecoveryContext(t, rpc, NonceRecoveryResync)
+	underpriced := &types.RpcError{Message: "transaction underpriced", Category: types.CategoryUnderpriced}
+	_, err = RecoverNonce(ctx, 0, underpriced)
+	require.ErrorIs(t, err, underpriced)
+
+	require.Empty(t, rpc.once)
+	require.Empty(t, rpc.retried)
+	require.Equal(t, uint64(3), ctx.Senders[0].GetNonce())
+	require.Zero(t, *ctx.NonceRecovery)
+}
@@ -2,13 +2,31 @@ package evmtx
 
 import (
 	"loadtester/interfaces"
+	"loadtester/metrics"
+	"loadtester/monitor"
+	"loadtester/report"
 	"loadtester/types"
 )
 
 // TransactionContext holds the configuration and RPC interfaces needed for transactions.
 type TransactionContext struct {
-	Config    *Config
-	EthRpc    interfaces.EthRpcRequester
-	Senders   []*types.Account
-	Receivers []*types.Account
+	Config        *Config
+	EthRpc        interfaces.EthRpcRequester
+	Senders       []*types.Account
+	Receivers     []*types.Account
+	Errors        *report.ErrorCounts
+	NonceRecovery *report.NonceRecoveryStats
+	Stages        *report.StageTimings
+	// Latency records the send latency of the current round
+	Latency *types.Histogram
+	// Receipts is nil when receipt tracking is disabled
+	Receipts *monitor.ReceiptTracker
+	// Sent is nil when reconciliation is disabled
+	Sent *monitor.SentTxs
+	// Visibility is nil when mempool visibility isn't sampled
+	Visibility *monitor.VisibilityProber
+	// Metrics is nil when no metrics endpoint is served
+	Metrics *metrics.Metrics
+	// Fees is nil when every tx pays the same gas price
+	Fees *monitor.TxFees
 }
@@ -0,0 +1,44 @@
+package htmlreport
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"loadtester/report"
+)
+
+const (
+	flagOutput = "output"
+
+	DefaultOutput = "report.html"
+)
+
+func NewReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report <run.json>",
+		Short: "Render a run file into a self-contained html report",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			output, _ := cmd.Flags().GetString(flagOutput)
+			run, err := report.ReadRunFile(args[0])
+			if err != nil {
+				return err
+			}
+			f, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			if err := report.RenderHTML(f, run); err != nil {
+				return errors.Wrap(err, "failed to render the report")
+			}
+			log.Info().Msgf("report written to %s", output)
+			return f.Close()
+		},
+	}
+	cmd.Flags().StringP(flagOutput, "o", DefaultOutput, "path of the html report")
+	return cmd
+}
@@ -20,12 +20,12 @@ const ONE_ETH = 1_000_000_000_000_000_000
 const EMPTY_CODEHASH = "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"
 
 // TODO: Refactor this function to use for other chains, it is only for Ethermint based chain currently.
-func NewEVMOSOffchainFeedingCmd(cfg Config) *cobra.Command {
+func NewEVMOSOffchainFeedingCmd(cfg *Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "evmos offchain_feeding",
 		Short: "Create multiple 1 eth acconts on the genesis",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			genesisBz := readGenesis(cfg)
+			genesisBz := readGenesis(*cfg)
 			accs, bals := loadAccountsAndBalances(genesisBz)
 			totalSupply := loadTotalSupply(genesisBz)
 			pkAccs, newAccs, newBals := make([]*types.Account, cfg.AccNum), make([]EthAccount, cfg.AccNum), make([]Balance, cfg.AccNum)
@@ -41,7 +41,7 @@ func NewEVMOSOffchainFeedingCmd(cfg Config) *cobra.Command {
 					defer wg.Done()
 					acc := utils.CreateRandomAcc()
 					pkAccs[idx] = acc
-					ethAcc, bal := createAccountAndBalance(cfg, acc, accountNumber)
+					ethAcc, bal := createAccountAndBalance(*cfg, acc, accountNumber)
 					newAccs[idx], newBals[idx] = ethAcc, bal
 				}(i, accNum)
 				accNum++
@@ -67,7 +67,7 @@ func NewEVMOSOffchainFeedingCmd(cfg Config) *cobra.Command {
 			totalSupply[0].Amount = new(big.Int).Add(originalTotalSupplyAmt, new(big.Int).Mul(big.NewInt(int64(cfg.AccNum)), big.NewInt(ONE_ETH))).String()
 			genesisBz["app_state"].(map[string]interface{})["bank"].(map[string]interface{})["supply"] = totalSupply
 
-			writeGenesis(cfg, genesisBz)
+			writeGenesis(*cfg, genesisBz)
 			return nil
 		},
 	}
@@ -78,12 +78,12 @@ type Account struct {
 	Balance string `json:"balance"`
 }
 
-func NewEVMOffchainFeedingCmd(cfg Config) *cobra.Command {
+func NewEVMOffchainFeedingCmd(cfg *Config) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "EVM offchain_feeding for EVM compatible chains",
 		Short: "Create multiple 1 eth acconts on the genesis",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			genesisBz := readGenesis(cfg)
+			genesisBz := readGenesis(*cfg)
 			pkAccs, newAccs, newBals := make([]*types.Account, cfg.AccNum), make([]EthAccount, cfg.AccNum), make([]Balance, cfg.AccNum)
 
 			currentLatestAccNum := len(newAccs) - 1
@@ -92,7 +92,7 @@ func NewEVMOffchainFeedingCmd(cfg Config) *cobra.Command {
 			for i := 0; i < cfg.AccNum; i++ {
 				acc := utils.CreateRandomAcc()
 				pkAccs[i] = acc
-				ethAcc, bal := createAccountAndBalance(cfg, acc, accNum)
+				ethAcc, bal := createAccountAndBalance(*cfg, acc, accNum)
 				newAccs[i], newBals[i] = ethAcc, bal
 
 				accHex := acc.EthAddr.Hex()
@@ -105,7 +105,7 @@ func NewEVMOffchainFeedingCmd(cfg Config) *cobra.Command {
 			// write private keys
 			utils.WritePrivateKeysToFile(pkAccs)
 
-			writeGenesis(cfg, genesisBz)
+			writeGenesis(*cfg, genesisBz)
 			return nil
 		},
 	}
@@ -9,8 +9,13 @@ import (
 	"github.com/spf13/cobra"
 
 	"loadtester/clients"
+	"loadtester/cmd/analyze"
+	"loadtester/cmd/compare"
 	"loadtester/cmd/evmtx"
+	"loadtester/cmd/htmlreport"
 	"loadtester/cmd/offchain_feeding"
+	"loadtester/interfaces"
+	"loadtester/tui"
 )
 
 var rootCmd = &cobra.Command{
@@ -38,10 +43,45 @@ func init() {
 | |__| |_| / ___ \| |_| || | | |___ ___) || | | |___|  _ < 
 |_____\___/_/   \_\____/ |_| |_____|____/ |_| |_____|_| \_\`)
 
-	log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: "15:04:05.000"}) // set pretty logging
-	cfg := MustRead(DefaultConfigPath)
-	ethRpc := clients.NewFastClient(cfg.CommonConfig.EthJsonRpcAddr)
-	rootCmd.AddCommand(evmtx.NewEvmTxCmd(cfg.EvmTxConfig, ethRpc))
-	rootCmd.AddCommand(offchain_feeding.NewEVMOSOffchainFeedingCmd(cfg.OffchainFeedingConfig))
-	rootCmd.AddCommand(offchain_feeding.NewEVMOffchainFeedingCmd(cfg.OffchainFeedingConfig))
+	tui.Logs = tui.NewLogSwitch(zerolog.ConsoleWriter{Out: os.Stderr, TimeFormat: "15:04:05.000"}) // set pretty logging
+	log.Logger = log.Output(tui.Logs)
+	// config.toml is only read, and the client only built, by the commands
+	// needing them, so the offline ones run without a config file
+	cfg := DefaultConfig()
+	var ethRpc interfaces.EthRpcRequester
+	loadConfig := func(*cobra.Command, []string) error {
+		read, err := ReadConfig(DefaultConfigPath)
+		if err != nil {
+			return err
+		}
+		cfg = *read
+		return nil
+	}
+	connect := func(cmd *cobra.Command, args []string) error {
+		if err := loadConfig(cmd, args); err != nil {
+			return err
+		}
+		ethRpc = clients.NewEthRpcRequester(cfg.CommonConfig)
+		return nil
+	}
+	// the compare thresholds fall back to their defaults without config.toml
+	loadOptionalConfig := func(cmd *cobra.Command, args []string) error {
+		if _, err := os.Stat(DefaultConfigPath); os.IsNotExist(err) {
+			return nil
+		}
+		return loadConfig(cmd, args)
+	}
+	getEthRpc := func() interfaces.EthRpcRequester { return ethRpc }
+
+	evmTxCmd := evmtx.NewEvmTxCmd(&cfg.EvmTxConfig, &cfg.CommonConfig, getEthRpc)
+	evmTxCmd.PersistentPreRunE = connect
+	analyzeCmd := analyze.NewAnalyzeCmd(getEthRpc)
+	analyzeCmd.PersistentPreRunE = connect
+	compareCmd := compare.NewCompareCmd(&cfg.CompareConfig)
+	compareCmd.PersistentPreRunE = loadOptionalConfig
+	evmosFeedingCmd := offchain_feeding.NewEVMOSOffchainFeedingCmd(&cfg.OffchainFeedingConfig)
+	evmosFeedingCmd.PersistentPreRunE = loadConfig
+	evmFeedingCmd := offchain_feeding.NewEVMOffchainFeedingCmd(&cfg.OffchainFeedingConfig)
+	evmFeedingCmd.PersistentPreRunE = loadConfig
+	rootCmd.AddCommand(evmTxCmd, analyzeCmd, htmlreport.NewReportCmd(), compareCmd, evmosFeedingCmd, evmFeedingCmd)
 }
@@ -0,0 +1,6 @@
+package interfaces
+
+// CometRpcCaller is implemented by requesters able to query cometbft rpc.
+type CometRpcCaller interface {
+	CometRpcCall(result interface{}, method string, params map[string]interface{}) error
+}
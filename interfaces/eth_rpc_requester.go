@@ -4,11 +4,19 @@ import (
 	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
+
+	"loadtester/types"
 )
 
 type EthRpcRequester interface {
 	EthSendRawTransaction(rawTx []byte) error
 	EthSendRawTransactionNoWaiting(rawTx []byte) error
 	EthPendingNonce(addr common.Address) (uint64, error)
-	EthSendMultipleRawTransactions(rawTxs [][]byte, cb func(*sync.Mutex, int)) (failed int64)
+	// EthSendMultipleRawTransactions sends all txs concurrently and calls cb
+	// once per tx with its index, the timing of its send and the error, nil on
+	// success. A nil error only means the tx was written when timing.Written
+	// is set.
+	EthSendMultipleRawTransactions(rawTxs [][]byte, cb func(*sync.Mutex, int, types.SendTiming, error)) (failed int64)
+	// RpcCall makes any json-rpc query and decodes its result into result.
+	RpcCall(result interface{}, method string, params ...interface{}) error
 }
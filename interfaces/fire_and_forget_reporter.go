@@ -0,0 +1,9 @@
+package interfaces
+
+import "loadtester/types"
+
+// FireAndForgetReporter is implemented by requesters able to send without
+// waiting for responses.
+type FireAndForgetReporter interface {
+	FireAndForgetStats() (stats types.FireAndForgetStats, ok bool)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "loadtester/types"
+
+// HealthChecker is implemented by requesters spreading traffic over several
+// endpoints and failing over between them.
+type HealthChecker interface {
+	StartHealthCheck() (stop func())
+	FailoverEvents() []types.FailoverEvent
+}
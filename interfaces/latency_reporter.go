@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"time"
+
+	"loadtester/types"
+)
+
+// LatencyReporter is implemented by requesters recording the latency of every
+// send attempt per endpoint. Endpoints are named by their address with its
+// credentials redacted, since the names are exported in reports and metrics.
+type LatencyReporter interface {
+	EndpointLatencies() map[string]types.LatencySummary
+	// ObserveLatencies calls observer with every latency recorded from now on
+	ObserveLatencies(observer func(endpoint string, d time.Duration))
+}
@@ -0,0 +1,8 @@
+package interfaces
+
+import "loadtester/types"
+
+// MempoolReporter is implemented by requesters able to query the mempool occupancy.
+type MempoolReporter interface {
+	MempoolStatus() (types.MempoolSample, error)
+}
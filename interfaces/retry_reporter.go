@@ -0,0 +1,8 @@
+package interfaces
+
+import "loadtester/types"
+
+// RetryReporter is implemented by requesters retrying failed sends.
+type RetryReporter interface {
+	RetryStats() types.RetryStats
+}
@@ -0,0 +1,7 @@
+package interfaces
+
+// SingleAttemptSender is implemented by requesters retrying failed sends. It
+// sends a tx once, bypassing the retry policy and its stats.
+type SingleAttemptSender interface {
+	EthSendRawTransactionOnce(rawTx []byte) error
+}
@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+
+	"loadtester/types"
+)
+
+const namespace = "loadtester"
+
+// Metrics exposes the progress of a run to prometheus. It is safe for
+// concurrent use.
+type Metrics struct {
+	registry   *prometheus.Registry
+	sent       prometheus.Counter
+	succeeded  prometheus.Counter
+	failed     *prometheus.CounterVec
+	inFlight   prometheus.Gauge
+	signing    prometheus.Histogram
+	send       prometheus.Histogram
+	rpc        *prometheus.HistogramVec
+	targetRate prometheus.Gauge
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		sent: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Name: "txs_sent_total",
+			Help: "Txs handed to the client for sending.",
+		}),
+		succeeded: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace, Name: "txs_succeeded_total",
+			Help: "Txs accepted by the node, resubmitted ones included.",
+		}),
+		failed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace, Name: "txs_failed_total",
+			Help: "Failed sends by error class.",
+		}, []string{"class"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "txs_in_flight",
+			Help: "Txs handed to the client whose send didn't complete yet.",
+		}),
+		signing: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "signing_seconds",
+			Help:    "Time to sign a tx and build its request body.",
+			Buckets: prometheus.ExponentialBuckets(0.00001, 2, 16),
+		}),
+		send: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "send_seconds",
+			Help:    "Time to send a tx, retries included.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+		}),
+		rpc: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace, Name: "rpc_request_seconds",
+			Help:    "Latency of single send attempts by endpoint.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+		}, []string{"endpoint"}),
+		targetRate: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace, Name: "target_tps",
+			Help: "Current target rate in txs per second.",
+		}),
+	}
+	m.registry.MustRegister(
+		m.sent, m.succeeded, m.failed, m.inFlight, m.signing, m.send, m.rpc, m.targetRate,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// Serve exposes the metrics at /metrics on addr until the returned stop is
+// called.
+func (m *Metrics) Serve(addr string) (stop func()) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Err(err).Msgf("metrics endpoint at %s failed", addr)
+		}
+	}()
+	log.Info().Msgf("serving metrics at http://%s/metrics", addr)
+	return func() { _ = srv.Close() }
+}
+
+// Sending records n txs handed to the client.
+func (m *Metrics) Sending(n int) {
+	m.sent.Add(float64(n))
+	m.inFlight.Add(float64(n))
+}
+
+// Sent records the outcome of a send, err is nil on success.
+func (m *Metrics) Sent(timing types.SendTiming, err error) {
+	m.inFlight.Dec()
+	m.send.Observe(timing.End.Sub(timing.Start).Seconds())
+	if err != nil {
+		m.failed.WithLabelValues(string(types.CategoryOf(err))).Inc()
+	}
+}
+
+// Succeeded records a tx accepted by the node.
+func (m *Metrics) Succeeded() {
+	m.succeeded.Inc()
+}
+
+func (m *Metrics) Signed(d time.Duration) {
+	m.signing.Observe(d.Seconds())
+}
+
+// ObserveRpc records a single send attempt against the endpoint. The endpoint
+// label is served unauthenticated, it must not carry credentials, which the
+// LatencyReporter names already have redacted.
+func (m *Metrics) ObserveRpc(endpoint string, d time.Duration) {
+	m.rpc.WithLabelValues(endpoint).Observe(d.Seconds())
+}
+
+// SetTargetRate records the target rate of tpu txs per time unit.
+func (m *Metrics) SetTargetRate(tpu int, timeUnit time.Duration) {
+	m.targetRate.Set(float64(tpu) / timeUnit.Seconds())
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+)
+
+func TestMetrics(t *testing.T) {
+	m := New()
+	m.SetTargetRate(500, 100*time.Millisecond)
+	m.Sending(3)
+	timing := types.SendTiming{Start: time.Now(), End: time.Now().Add(5 * time.Millisecond)}
+	m.Sent(timing, nil)
+	m.Succeeded()
+	m.Sent(timing, &types.RpcError{Category: types.CategoryUnderpriced})
+	m.ObserveRpc("http://node-0:8545", 3*time.Millisecond)
+
+	require.Equal(t, 5000.0, testutil.ToFloat64(m.targetRate))
+	require.Equal(t, 3.0, testutil.ToFloat64(m.sent))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.succeeded))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.inFlight))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.failed.WithLabelValues(string(types.CategoryUnderpriced))))
+	require.Equal(t, 1, testutil.CollectAndCount(m.rpc))
+	require.Equal(t, 1, testutil.CollectAndCount(m.send))
+}
@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/rs/zerolog/log"
+
+	"loadtester/interfaces"
+	"loadtester/types"
+)
+
+// intrinsicGas is the gas used by a plain eth transfer.
+const intrinsicGas = 21000
+
+// AccountState is the on-chain nonce and balance of an account.
+type AccountState struct {
+	Nonce   uint64
+	Balance *big.Int
+}
+
+// TxCost is what a single tx of the run costs its sender. Value is zero for
+// transfers to self, since the sender gets it back.
+type TxCost struct {
+	Value    *big.Int
+	GasLimit uint64
+	GasPrice *big.Int
+	// MaxGasPrice is the highest gas price when the fees of the txs vary, nil
+	// if every tx pays GasPrice
+	MaxGasPrice *big.Int
+}
+
+// bounds returns the spend of n txs when they use the intrinsic gas only and
+// when they use their whole gas limit.
+func (c TxCost) bounds(n uint64) (min, max *big.Int) {
+	count := new(big.Int).SetUint64(n)
+	value := new(big.Int).Mul(c.Value, count)
+	minFee := new(big.Int).Mul(c.GasPrice, new(big.Int).SetUint64(intrinsicGas*n))
+	maxGasPrice := c.GasPrice
+	if c.MaxGasPrice != nil {
+		maxGasPrice = c.MaxGasPrice
+	}
+	maxFee := new(big.Int).Mul(maxGasPrice, new(big.Int).SetUint64(c.GasLimit*n))
+	return minFee.Add(minFee, value), maxFee.Add(maxFee, value)
+}
+
+// FetchAccountStates queries the on-chain state of every address.
+func FetchAccountStates(rpc interfaces.EthRpcRequester, addrs []common.Address) map[common.Address]AccountState {
+	states := make(map[common.Address]AccountState, len(addrs))
+	mu := sync.Mutex{}
+	sem := make(chan struct{}, rpcConcurrency)
+	wg := sync.WaitGroup{}
+	for _, addr := range addrs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(addr common.Address) {
+			defer func() { <-sem; wg.Done() }()
+			state, err := fetchAccountState(rpc, addr)
+			if err != nil {
+				log.Debug().Err(err).Msgf("failed to fetch the state of %s", addr.Hex())
+				return
+			}
+			mu.Lock()
+			states[addr] = state
+			mu.Unlock()
+		}(addr)
+	}
+	wg.Wait()
+	return states
+}
+
+func fetchAccountState(rpc interfaces.EthRpcRequester, addr common.Address) (AccountState, error) {
+	nonce, err := rpc.EthPendingNonce(addr)
+	if err != nil {
+		return AccountState{}, err
+	}
+	var balance hexutil.Big
+	if err := rpc.RpcCall(&balance, "eth_getBalance", addr, "latest"); err != nil {
+		return AccountState{}, err
+	}
+	return AccountState{Nonce: nonce, Balance: balance.ToInt()}, nil
+}
+
+// VerifyAccounts compares the on-chain state of the senders with their local
+// nonce and with the spend expected from the txs included since start.
+func VerifyAccounts(
+	rpc interfaces.EthRpcRequester, senders []*types.Account, start map[common.Address]AccountState, cost TxCost,
+) types.AccountVerification {
+	addrs := make([]common.Address, 0, len(senders))
+	for _, sender := range senders {
+		addrs = append(addrs, sender.EthAddr)
+	}
+	end := FetchAccountStates(rpc, addrs)
+
+	var v types.AccountVerification
+	for _, sender := range senders {
+		before, okBefore := start[sender.EthAddr]
+		after, okAfter := end[sender.EthAddr]
+		if !okBefore || !okAfter {
+			v.Failed++
+			continue
+		}
+		v.Checked++
+		mismatch := types.AccountMismatch{
+			Address:      sender.EthAddr,
+			LocalNonce:   sender.Nonce,
+			OnchainNonce: after.Nonce,
+			Spend:        new(big.Int).Sub(before.Balance, after.Balance),
+			NonceDrift:   sender.Nonce != after.Nonce,
+		}
+		included := uint64(0)
+		if after.Nonce > before.Nonce {
+			included = after.Nonce - before.Nonce
+		}
+		mismatch.ExpectedMin, mismatch.ExpectedMax = cost.bounds(included)
+		mismatch.BalanceOff = mismatch.Spend.Cmp(mismatch.ExpectedMin) < 0 || mismatch.Spend.Cmp(mismatch.ExpectedMax) > 0
+		if mismatch.NonceDrift {
+			v.NonceDrifts++
+		}
+		if mismatch.BalanceOff {
+			v.BalanceOffs++
+		}
+		if mismatch.NonceDrift || mismatch.BalanceOff {
+			v.Mismatches = append(v.Mismatches, mismatch)
+		}
+	}
+	return v
+}
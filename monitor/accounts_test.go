@@ -0,0 +1,43 @@
+package monitor
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+)
+
+func TestVerifyAccounts(t *testing.T) {
+	chain := newFakeChain()
+	ok, drifted, overspent := common.HexToAddress("0xa"), common.HexToAddress("0xb"), common.HexToAddress("0xc")
+	for _, addr := range []common.Address{ok, drifted, overspent} {
+		chain.balances[addr] = big.NewInt(1_000_000)
+	}
+	start := FetchAccountStates(chain, []common.Address{ok, drifted, overspent})
+	require.Len(t, start, 3)
+
+	// each tx costs 10 + 21000..30000 gas at price 1
+	cost := TxCost{Value: big.NewInt(10), GasLimit: 30000, GasPrice: big.NewInt(1)}
+	chain.nonces[ok], chain.balances[ok] = 2, big.NewInt(1_000_000-2*(10+25000))
+	chain.nonces[drifted], chain.balances[drifted] = 1, big.NewInt(1_000_000-(10+21000))
+	chain.nonces[overspent], chain.balances[overspent] = 1, big.NewInt(1_000_000-(10+40000))
+
+	senders := []*types.Account{
+		{EthAddr: ok, Nonce: 2},
+		{EthAddr: drifted, Nonce: 2}, // the second tx never made it
+		{EthAddr: overspent, Nonce: 1},
+	}
+	v := VerifyAccounts(chain, senders, start, cost)
+	require.Equal(t, 3, v.Checked)
+	require.Equal(t, 1, v.NonceDrifts)
+	require.Equal(t, 1, v.BalanceOffs)
+	require.Len(t, v.Mismatches, 2)
+	require.Equal(t, drifted, v.Mismatches[0].Address)
+	require.True(t, v.Mismatches[0].NonceDrift)
+	require.Equal(t, overspent, v.Mismatches[1].Address)
+	require.True(t, v.Mismatches[1].BalanceOff)
+	require.Equal(t, big.NewInt(40010), v.Mismatches[1].Spend)
+}
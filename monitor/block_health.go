@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"loadtester/types"
+)
+
+const (
+	// the block time is jittery if its standard deviation exceeds this fraction of the mean
+	maxJitterRatio = 0.5
+	// a block stalled if it took this many times the mean block time
+	stallFactor = 3
+)
+
+// BlockHealth analyses blocks[1:], the first block only marks the start of the
+// range. loadStop is when sending stopped, zero if unknown, and ours the
+// hashes of the txs of the run. The chain caught up at the first block after
+// the load stopped which follows every block holding txs of the run, other
+// traffic may go on. Block timestamps are whole seconds compared with the wall
+// clock loadStop, so CatchUp and EmptyUnderLoad are precise to about a second.
+func BlockHealth(blocks []types.Block, loadStop time.Time, ours map[string]bool) types.BlockHealth {
+	var h types.BlockHealth
+	if len(blocks) < 2 {
+		return h
+	}
+	h.Blocks = len(blocks) - 1
+
+	var intervals []float64
+	var txs int64
+	var gasUsed uint64
+	var ratioSum float64
+	var ratioBlocks int
+	started := false   // blocks before the first txs arrived aren't under load yet
+	afterStop := false // whether any block came after the load stopped
+	lastOurs := -1     // the last block holding txs of the run
+	for i, block := range blocks[1:] {
+		prev := blocks[i]
+		intervals = append(intervals, float64(block.Timestamp-prev.Timestamp))
+		txs += int64(len(block.Transactions))
+		gasUsed += uint64(block.GasUsed)
+		blockTime := time.Unix(int64(block.Timestamp), 0)
+		afterStop = afterStop || (!loadStop.IsZero() && blockTime.After(loadStop))
+		if len(block.Transactions) == 0 {
+			h.EmptyBlocks++
+			if started && !loadStop.IsZero() && !blockTime.After(loadStop) {
+				h.EmptyUnderLoad++
+			}
+		}
+		for _, hash := range block.Transactions {
+			if ours[hash.Hex()] {
+				lastOurs = i
+				break
+			}
+		}
+		started = started || len(block.Transactions) > 0
+		if block.GasLimit > 0 {
+			h.GasLimit = uint64(block.GasLimit)
+			ratio := float64(block.GasUsed) / float64(block.GasLimit)
+			ratioSum += ratio
+			ratioBlocks++
+			h.GasUsedRatioMax = math.Max(h.GasUsedRatioMax, ratio)
+		}
+	}
+	if !loadStop.IsZero() {
+		for _, block := range blocks[lastOurs+2:] {
+			if blockTime := time.Unix(int64(block.Timestamp), 0); blockTime.After(loadStop) {
+				h.CaughtUp, h.CatchUp = true, blockTime.Sub(loadStop)
+				break
+			}
+		}
+	}
+	if ratioBlocks > 0 {
+		h.GasUsedRatioMean = ratioSum / float64(ratioBlocks)
+	}
+
+	var mean, variance, max float64
+	for _, s := range intervals {
+		mean += s
+		max = math.Max(max, s)
+	}
+	mean /= float64(len(intervals))
+	for _, s := range intervals {
+		variance += (s - mean) * (s - mean)
+	}
+	variance /= float64(len(intervals))
+	seconds := func(s float64) time.Duration { return time.Duration(s * float64(time.Second)) }
+	h.BlockTimeMean, h.BlockTimeJitter, h.BlockTimeMax = seconds(mean), seconds(math.Sqrt(variance)), seconds(max)
+
+	if mean > 0 {
+		h.AchievedTps = float64(txs) / (mean * float64(h.Blocks))
+		gasPerTx := float64(intrinsicGas)
+		if txs > 0 {
+			gasPerTx = float64(gasUsed) / float64(txs)
+		}
+		h.TheoreticalMaxTps = float64(h.GasLimit) / gasPerTx / mean
+		if h.TheoreticalMaxTps > 0 {
+			h.Utilization = h.AchievedTps / h.TheoreticalMaxTps
+		}
+	}
+
+	if mean > 0 && math.Sqrt(variance) > maxJitterRatio*mean {
+		h.Issues = append(h.Issues, fmt.Sprintf("block time jitter %v is above %.0f%% of the mean %v",
+			h.BlockTimeJitter, 100*maxJitterRatio, h.BlockTimeMean))
+	}
+	if mean > 0 && max > stallFactor*mean {
+		h.Issues = append(h.Issues, fmt.Sprintf("the longest block time %v is more than %dx the mean", h.BlockTimeMax, stallFactor))
+	}
+	if h.EmptyUnderLoad > 0 {
+		h.Issues = append(h.Issues, fmt.Sprintf("%d empty blocks while txs were being sent", h.EmptyUnderLoad))
+	}
+	// without any block after the stop, e.g. when pending txs weren't waited
+	// for, there is nothing to tell whether the chain caught up
+	if afterStop && !h.CaughtUp {
+		h.Issues = append(h.Issues, "the chain was still behind when the run ended, the last block held txs of the run")
+	}
+	h.Healthy = len(h.Issues) == 0
+	return h
+}
@@ -0,0 +1,69 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+)
+
+func TestBlockHealth(t *testing.T) {
+	tx, other := []common.Hash{common.HexToHash("0x1")}, []common.Hash{common.HexToHash("0x2")}
+	ours := map[string]bool{tx[0].Hex(): true}
+	block := func(ts uint64, txs []common.Hash) types.Block {
+		return types.Block{
+			Timestamp:    hexutil.Uint64(ts),
+			GasLimit:     210000,
+			GasUsed:      hexutil.Uint64(21000 * len(txs)),
+			Transactions: txs,
+		}
+	}
+	blocks := []types.Block{
+		block(100, nil), // start of the range
+		block(101, nil), // before the first txs, not under load
+		block(102, tx),
+		block(103, nil), // empty under load
+		block(104, tx),
+		block(105, tx),    // still including txs of the run after the load stopped
+		block(106, other), // caught up, background traffic goes on
+	}
+	h := BlockHealth(blocks, time.Unix(104, 0), ours)
+	require.Equal(t, 6, h.Blocks)
+	require.Equal(t, time.Second, h.BlockTimeMean)
+	require.Zero(t, h.BlockTimeJitter)
+	require.Equal(t, 2, h.EmptyBlocks)
+	require.Equal(t, 1, h.EmptyUnderLoad)
+	require.True(t, h.CaughtUp)
+	require.Equal(t, 2*time.Second, h.CatchUp)
+	require.InDelta(t, 0.4/6, h.GasUsedRatioMean, 1e-9)
+	require.InDelta(t, 10, h.TheoreticalMaxTps, 1e-9)
+	require.InDelta(t, 4.0/6, h.AchievedTps, 1e-9)
+	require.False(t, h.Healthy)
+	require.Len(t, h.Issues, 1)
+}
+
+func TestBlockHealthEndOfRange(t *testing.T) {
+	tx := []common.Hash{common.HexToHash("0x1")}
+	ours := map[string]bool{tx[0].Hex(): true}
+	blocks := []types.Block{
+		{Timestamp: 100},
+		{Timestamp: 101, GasLimit: 210000, GasUsed: 21000, Transactions: tx},
+		{Timestamp: 102, GasUsed: 21000, Transactions: tx}, // no gas limit reported
+	}
+	// no block after the load stopped, whether the chain caught up is unknown
+	h := BlockHealth(blocks, time.Unix(102, 500), ours)
+	require.False(t, h.CaughtUp)
+	require.True(t, h.Healthy, h.Issues)
+	// only blocks with a gas limit count in the gas used ratio
+	require.InDelta(t, 0.1, h.GasUsedRatioMean, 1e-9)
+
+	// a last block after the stop holding txs of the run means the chain is behind
+	blocks = append(blocks, types.Block{Timestamp: 104, GasLimit: 210000, GasUsed: 21000, Transactions: tx})
+	h = BlockHealth(blocks, time.Unix(102, 500), ours)
+	require.False(t, h.Healthy)
+	require.Len(t, h.Issues, 1)
+}
@@ -0,0 +1,50 @@
+package monitor
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"loadtester/interfaces"
+	"loadtester/types"
+)
+
+// txResult is the part of a cometbft tx result the analysis looks at, int64
+// fields are encoded as strings.
+type txResult struct {
+	Code      uint32 `json:"code"`
+	Codespace string `json:"codespace"`
+	GasWanted string `json:"gas_wanted"`
+	GasUsed   string `json:"gas_used"`
+}
+
+// FetchBlockResults aggregates the cometbft block_results of the blocks from,
+// to, both included.
+func FetchBlockResults(comet interfaces.CometRpcCaller, from, to uint64) (types.BlockResultStats, error) {
+	stats := types.BlockResultStats{Codes: make(map[string]int64)}
+	mu := sync.Mutex{}
+	err := forEachBlock(from, to, func(n uint64) error {
+		var results struct {
+			TxsResults []txResult `json:"txs_results"`
+		}
+		params := map[string]interface{}{"height": strconv.FormatUint(n, 10)}
+		if err := comet.CometRpcCall(&results, "block_results", params); err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, res := range results.TxsResults {
+			stats.Txs++
+			gasWanted, _ := strconv.ParseInt(res.GasWanted, 10, 64)
+			gasUsed, _ := strconv.ParseInt(res.GasUsed, 10, 64)
+			stats.GasWanted += gasWanted
+			stats.GasUsed += gasUsed
+			if res.Code != 0 {
+				stats.Failed++
+				stats.Codes[fmt.Sprintf("%s:%d", res.Codespace, res.Code)]++
+			}
+		}
+		return nil
+	})
+	return stats, err
+}
@@ -0,0 +1,29 @@
+package monitor
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeComet map[string]string
+
+func (f fakeComet) CometRpcCall(result interface{}, method string, params map[string]interface{}) error {
+	return json.Unmarshal([]byte(f[params["height"].(string)]), result)
+}
+
+func TestFetchBlockResults(t *testing.T) {
+	comet := fakeComet{
+		"5": `{"txs_results":[{"code":0,"gas_wanted":"21000","gas_used":"21000"},{"code":11,"codespace":"sdk","gas_wanted":"30000","gas_used":"30000"}]}`,
+		"6": `{"txs_results":null}`,
+		"7": `{"txs_results":[{"code":11,"codespace":"sdk","gas_wanted":"50000","gas_used":"50000"}]}`,
+	}
+	stats, err := FetchBlockResults(comet, 5, 7)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), stats.Txs)
+	require.Equal(t, int64(2), stats.Failed)
+	require.Equal(t, int64(101000), stats.GasWanted)
+	require.Equal(t, int64(101000), stats.GasUsed)
+	require.Equal(t, map[string]int64{"sdk:11": 2}, stats.Codes)
+}
@@ -0,0 +1,162 @@
+package monitor
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"loadtester/types"
+)
+
+// TxFees records the gas price, sender and send time of every tx of the run.
+type TxFees struct {
+	mu  sync.Mutex
+	txs map[common.Hash]feeTx
+}
+
+type feeTx struct {
+	gasPrice int64
+	sender   common.Address
+	sentAt   time.Time
+}
+
+func NewTxFees() *TxFees {
+	return &TxFees{txs: make(map[common.Hash]feeTx)}
+}
+
+func (f *TxFees) Add(txHash string, sender common.Address, gasPrice int64, sentAt time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.txs[common.HexToHash(txHash)] = feeTx{gasPrice: gasPrice, sender: sender, sentAt: sentAt}
+}
+
+// FeeOrdering compares the inclusion order of the recorded txs in blocks with
+// their gas price. Txs of other senders are ignored.
+//
+// Only pairs of txs which competed for inclusion are compared: txs of the same
+// block, and txs of different blocks when the later one was sent before the
+// block of the earlier one was produced. Block timestamps have a resolution of
+// a second and come from the node's clock, so a pair sent around the block
+// time may be left out. Pairs of the same sender are left out as well, their
+// nonces force their order whatever their fees.
+func FeeOrdering(blocks []types.Block, fees *TxFees) types.FeeOrdering {
+	fees.mu.Lock()
+	defer fees.mu.Unlock()
+
+	var ordering types.FeeOrdering
+	var all []feeTx            // in inclusion order
+	var blockStart []int       // index in all of the first tx of the block of each tx
+	var blockStarts []int      // index in all of the first tx of each block holding txs
+	var blockTimes []time.Time // and their timestamps
+	for _, block := range blocks {
+		start := len(all)
+		for _, hash := range block.Transactions {
+			if tx, ok := fees.txs[hash]; ok {
+				all = append(all, tx)
+				blockStart = append(blockStart, start)
+			}
+		}
+		if len(all) == start {
+			continue
+		}
+		if len(all)-start >= 2 {
+			ordering.Blocks++
+		}
+		blockStarts = append(blockStarts, start)
+		blockTimes = append(blockTimes, time.Unix(int64(block.Timestamp), 0))
+	}
+	ordering.Txs = int64(len(all))
+
+	// a tx competed with the txs of the blocks produced after it was sent, up
+	// to its own block
+	competing := make([]int, len(all))
+	for i, tx := range all {
+		b := sort.Search(len(blockTimes), func(b int) bool { return blockTimes[b].After(tx.sentAt) })
+		competing[i] = blockStart[i]
+		if b < len(blockStarts) && blockStarts[b] < competing[i] {
+			competing[i] = blockStarts[b]
+		}
+	}
+	ordering.Inversions, ordering.Pairs = feeInversions(all, competing)
+	ordering.Correlation = kendallCorrelation(ordering.Inversions, ordering.Pairs)
+	ordering.BlockInversions, ordering.BlockPairs = feeInversions(all, blockStart)
+	ordering.BlockCorrelation = kendallCorrelation(ordering.BlockInversions, ordering.BlockPairs)
+	return ordering
+}
+
+// feeInversions counts, for every tx given in inclusion order, the earlier txs
+// from starts[i] on of other senders which paid a lower fee, and those which
+// paid a different fee.
+func feeInversions(txs []feeTx, starts []int) (inversions, pairs int64) {
+	ranks, distinct := denseRanks(txs)
+	// tree is a fenwick tree counting the fees seen so far per rank
+	tree := make([]int64, distinct+1)
+	below := func(rank int) (n int64) {
+		for j := rank; j > 0; j -= j & -j {
+			n += tree[j]
+		}
+		return n
+	}
+	// the counts before starts[i] are subtracted once the sweep reaches it
+	startsAt := make([][]int, len(txs))
+	for i, start := range starts {
+		startsAt[start] = append(startsAt[start], i)
+	}
+	counts := make([]int64, distinct)
+	bySender := make(map[common.Address][]int)
+	for p, rank := range ranks {
+		for _, i := range startsAt[p] {
+			inversions -= below(ranks[i])
+			pairs -= int64(p) - counts[ranks[i]]
+		}
+		inversions += below(rank)
+		pairs += int64(p) - counts[rank]
+		for j := rank + 1; j <= distinct; j += j & -j {
+			tree[j]++
+		}
+		counts[rank]++
+
+		for _, q := range bySender[txs[p].sender] {
+			if q < starts[p] || ranks[q] == rank {
+				continue
+			}
+			pairs--
+			if ranks[q] < rank {
+				inversions--
+			}
+		}
+		bySender[txs[p].sender] = append(bySender[txs[p].sender], p)
+	}
+	return inversions, pairs
+}
+
+// denseRanks maps the fees to 0..distinct-1 keeping their order.
+func denseRanks(txs []feeTx) (ranks []int, distinct int) {
+	sorted := make([]int64, len(txs))
+	for i, tx := range txs {
+		sorted[i] = tx.gasPrice
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	index := make(map[int64]int)
+	for _, fee := range sorted {
+		if _, ok := index[fee]; !ok {
+			index[fee] = len(index)
+		}
+	}
+	ranks = make([]int, len(txs))
+	for i, tx := range txs {
+		ranks[i] = index[tx.gasPrice]
+	}
+	return ranks, len(index)
+}
+
+// kendallCorrelation returns the kendall rank correlation between the fees and
+// the inclusion position over the compared pairs.
+func kendallCorrelation(inversions, pairs int64) float64 {
+	if pairs == 0 {
+		return 0
+	}
+	return float64(2*inversions-pairs) / float64(pairs)
+}
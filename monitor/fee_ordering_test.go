@@ -0,0 +1,58 @@
+package monitor
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+)
+
+func TestFeeOrdering(t *testing.T) {
+	fees := NewTxFees()
+	hash := func(i int64) common.Hash { return common.BigToHash(big.NewInt(i)) }
+	sender := func(s string) common.Address { return common.HexToAddress(s) }
+	at := func(ms int64) time.Time { return time.UnixMilli(ms) }
+	fees.Add(hash(1).Hex(), sender("0xa"), 3, at(99000))
+	fees.Add(hash(2).Hex(), sender("0xb"), 2, at(99000))
+	fees.Add(hash(3).Hex(), sender("0xc"), 1, at(99000))
+	fees.Add(hash(4).Hex(), sender("0xe"), 3, at(100500)) // after block 10
+	fees.Add(hash(5).Hex(), sender("0xd"), 1, at(99500))
+	fees.Add(hash(6).Hex(), sender("0xc"), 3, at(99500)) // same sender as tx 3
+	fees.Add(hash(7).Hex(), sender("0xf"), 2, at(101500)) // after block 11
+	other := common.HexToHash("0xff")
+	blocks := []types.Block{
+		{Number: 10, Timestamp: 100, Transactions: []common.Hash{hash(1), other, hash(2), hash(3)}}, // fees 3, 2, 1
+		{Number: 11, Timestamp: 101, Transactions: []common.Hash{hash(5), hash(4)}},                 // fees 1, 3
+		{Number: 12, Timestamp: 102, Transactions: []common.Hash{hash(6), hash(7)}},                 // fees 3, 2
+	}
+	ordering := FeeOrdering(blocks, fees)
+	require.Equal(t, int64(7), ordering.Txs)
+	require.Equal(t, 3, ordering.Blocks)
+	// tx 4 only competed with tx 5 (1<3), tx 7 only with tx 6, tx 6 with
+	// every earlier tx but tx 3 (1<3, 1<3, 2<3)
+	require.Equal(t, int64(3), ordering.Inversions)
+	require.Equal(t, int64(9), ordering.Pairs)
+	require.InDelta(t, -1.0/3, ordering.Correlation, 1e-9)
+	require.Equal(t, int64(1), ordering.BlockInversions)
+	require.Equal(t, int64(5), ordering.BlockPairs)
+	require.InDelta(t, -0.6, ordering.BlockCorrelation, 1e-9)
+}
+
+func TestFeeOrderingPerfect(t *testing.T) {
+	fees := NewTxFees()
+	var txs []common.Hash
+	for i := int64(10); i > 0; i-- {
+		h := common.BigToHash(big.NewInt(i))
+		fees.Add(h.Hex(), common.BigToAddress(big.NewInt(i)), i, time.Unix(0, 0))
+		txs = append(txs, h)
+	}
+	ordering := FeeOrdering([]types.Block{{Timestamp: 1, Transactions: txs}}, fees)
+	require.Zero(t, ordering.Inversions)
+	require.Equal(t, int64(45), ordering.Pairs)
+	require.InDelta(t, -1, ordering.Correlation, 1e-9)
+	require.InDelta(t, -1, ordering.BlockCorrelation, 1e-9)
+}
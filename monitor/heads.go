@@ -0,0 +1,161 @@
+package monitor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/rs/zerolog/log"
+
+	"loadtester/types"
+)
+
+// txCountTimeout bounds the query of the tx count of a block.
+const txCountTimeout = 5 * time.Second
+
+// header is the part of a newHeads notification the monitor looks at.
+type header struct {
+	Hash     common.Hash    `json:"hash"`
+	Number   hexutil.Uint64 `json:"number"`
+	GasUsed  hexutil.Uint64 `json:"gasUsed"`
+	GasLimit hexutil.Uint64 `json:"gasLimit"`
+}
+
+// txCountRequest asks for the tx count of the idx-th sample.
+type txCountRequest struct {
+	idx  int
+	hash common.Hash
+}
+
+// HeadMonitor subscribes to newHeads over websocket and timestamps every block
+// on arrival, without adding polling load to the node.
+type HeadMonitor struct {
+	client *rpc.Client
+	heads  chan uint64 // block numbers for the receipt tracker
+
+	mu      sync.Mutex // guards samples
+	samples []types.BlockSample
+
+	// tx counts are fetched by a worker so the queries don't delay the
+	// arrival time of the next heads
+	txCounts     chan txCountRequest
+	txCountsDone chan struct{}
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewHeadMonitor(wsAddr string) (*HeadMonitor, error) {
+	client, err := rpc.Dial(wsAddr)
+	if err != nil {
+		return nil, err
+	}
+	return &HeadMonitor{
+		client:       client,
+		heads:        make(chan uint64, 64),
+		txCounts:     make(chan txCountRequest, 1024),
+		txCountsDone: make(chan struct{}),
+		done:         make(chan struct{}),
+	}, nil
+}
+
+// Heads returns the numbers of the arriving blocks. Numbers are dropped when
+// the reader falls behind, so readers must catch up to the latest one.
+func (hm *HeadMonitor) Heads() <-chan uint64 {
+	return hm.heads
+}
+
+// Start subscribes to newHeads until Stop is called.
+func (hm *HeadMonitor) Start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	headCh := make(chan header, 64)
+	headSub, err := hm.client.EthSubscribe(ctx, headCh, "newHeads")
+	if err != nil {
+		cancel()
+		return err
+	}
+	hm.cancel = cancel
+
+	go hm.fetchTxCounts()
+	go func() {
+		defer close(hm.done)
+		defer close(hm.heads)
+		defer headSub.Unsubscribe()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-headSub.Err():
+				log.Err(err).Msg("newHeads subscription ended")
+				return
+			case h := <-headCh:
+				hm.record(h, time.Now())
+			}
+		}
+	}()
+	return nil
+}
+
+// record samples the block and queues the query of its tx count, which
+// newHeads doesn't carry.
+func (hm *HeadMonitor) record(h header, arrivedAt time.Time) {
+	sample := types.BlockSample{
+		Number:    uint64(h.Number),
+		ArrivedAt: arrivedAt,
+		GasUsed:   uint64(h.GasUsed),
+		GasLimit:  uint64(h.GasLimit),
+	}
+	hm.mu.Lock()
+	if n := len(hm.samples); n > 0 {
+		sample.Interval = arrivedAt.Sub(hm.samples[n-1].ArrivedAt)
+	}
+	hm.samples = append(hm.samples, sample)
+	idx := len(hm.samples) - 1
+	hm.mu.Unlock()
+
+	select {
+	case hm.txCounts <- txCountRequest{idx: idx, hash: h.Hash}:
+	default:
+		log.Debug().Msgf("tx count queue full, skipping block %d", sample.Number)
+	}
+	select {
+	case hm.heads <- sample.Number:
+	default:
+	}
+}
+
+// fetchTxCounts fills in the tx count of the queued samples until the queue
+// is closed.
+func (hm *HeadMonitor) fetchTxCounts() {
+	defer close(hm.txCountsDone)
+	for req := range hm.txCounts {
+		ctx, cancel := context.WithTimeout(context.Background(), txCountTimeout)
+		var txs hexutil.Uint
+		err := hm.client.CallContext(ctx, &txs, "eth_getBlockTransactionCountByHash", req.hash)
+		cancel()
+		if err != nil {
+			log.Debug().Err(err).Msgf("failed to fetch the tx count of block %s", req.hash.Hex())
+			continue
+		}
+		hm.mu.Lock()
+		hm.samples[req.idx].Txs = int(txs)
+		hm.mu.Unlock()
+	}
+}
+
+// Stop ends the subscription, waits for the queued tx counts and returns the
+// blocks received so far.
+func (hm *HeadMonitor) Stop() []types.BlockSample {
+	hm.cancel()
+	<-hm.done
+	close(hm.txCounts)
+	<-hm.txCountsDone
+	hm.client.Close()
+
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	return hm.samples
+}
@@ -0,0 +1,64 @@
+package monitor
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEthService emits three heads, each with two txs.
+type fakeEthService struct{}
+
+func (fakeEthService) NewHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, _ := rpc.NotifierFromContext(ctx)
+	sub := notifier.CreateSubscription()
+	go func() {
+		for n := 1; n <= 3; n++ {
+			time.Sleep(10 * time.Millisecond)
+			_ = notifier.Notify(sub.ID, header{Number: hexutil.Uint64(n), GasUsed: 42000, GasLimit: 100000})
+		}
+	}()
+	return sub, nil
+}
+
+func (fakeEthService) GetBlockTransactionCountByHash(common.Hash) hexutil.Uint {
+	return 2
+}
+
+func TestHeadMonitor(t *testing.T) {
+	server := rpc.NewServer()
+	require.NoError(t, server.RegisterName("eth", fakeEthService{}))
+	srv := httptest.NewServer(server.WebsocketHandler([]string{"*"}))
+	defer srv.Close()
+
+	hm, err := NewHeadMonitor("ws" + strings.TrimPrefix(srv.URL, "http"))
+	require.NoError(t, err)
+	require.NoError(t, hm.Start())
+
+	var heads []uint64
+	for len(heads) < 3 {
+		select {
+		case n := <-hm.Heads():
+			heads = append(heads, n)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for heads")
+		}
+	}
+	require.Equal(t, []uint64{1, 2, 3}, heads)
+
+	samples := hm.Stop()
+	require.Len(t, samples, 3)
+	for _, sample := range samples {
+		require.Equal(t, 2, sample.Txs)
+	}
+	require.Equal(t, uint64(42000), samples[0].GasUsed)
+	require.Zero(t, samples[0].Interval)
+	require.Positive(t, samples[2].Interval)
+}
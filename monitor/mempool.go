@@ -0,0 +1,85 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"loadtester/interfaces"
+	"loadtester/types"
+)
+
+// MempoolMonitor samples the mempool occupancy at a fixed interval.
+type MempoolMonitor struct {
+	source   interfaces.MempoolReporter
+	interval time.Duration
+
+	mu      sync.Mutex
+	samples []types.MempoolSample
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func NewMempoolMonitor(source interfaces.MempoolReporter, interval time.Duration) *MempoolMonitor {
+	return &MempoolMonitor{
+		source:   source,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start samples the mempool until Stop is called.
+func (mm *MempoolMonitor) Start() {
+	go func() {
+		defer close(mm.done)
+		ticker := time.NewTicker(mm.interval)
+		defer ticker.Stop()
+		mm.sample()
+		for {
+			select {
+			case <-mm.stop:
+				return
+			case <-ticker.C:
+				mm.sample()
+			}
+		}
+	}()
+}
+
+func (mm *MempoolMonitor) sample() {
+	sample, err := mm.source.MempoolStatus()
+	if err != nil {
+		log.Debug().Err(err).Msg("failed to sample the mempool")
+		return
+	}
+	mm.mu.Lock()
+	mm.samples = append(mm.samples, sample)
+	mm.mu.Unlock()
+}
+
+// Samples returns the samples taken so far.
+func (mm *MempoolMonitor) Samples() []types.MempoolSample {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	return append([]types.MempoolSample(nil), mm.samples...)
+}
+
+// Latest returns the last sample, ok is false if none was taken yet.
+func (mm *MempoolMonitor) Latest() (sample types.MempoolSample, ok bool) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	if len(mm.samples) == 0 {
+		return types.MempoolSample{}, false
+	}
+	return mm.samples[len(mm.samples)-1], true
+}
+
+// Stop stops sampling and returns every sample.
+func (mm *MempoolMonitor) Stop() []types.MempoolSample {
+	close(mm.stop)
+	<-mm.done
+	return mm.Samples()
+}
@@ -0,0 +1,212 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+
+	"loadtester/interfaces"
+	"loadtester/types"
+)
+
+// rpcConcurrency bounds the concurrent queries of a batch, e.g. the receipts of a block.
+const rpcConcurrency = 16
+
+// seenBlocks is how many blocks the txs included before being tracked are
+// kept for. Other txs of the chain are dropped after that, so a shared chain
+// doesn't grow the tracker without bound.
+const seenBlocks = 32
+
+// ReceiptTracker watches new blocks for the sent txs and fetches the receipts
+// of the included ones. Inclusion is timestamped when the block is first seen,
+// so latencies are precise to the poll interval.
+type ReceiptTracker struct {
+	rpc          interfaces.EthRpcRequester
+	pollInterval time.Duration
+
+	mu        sync.Mutex // guards every field below
+	sent      map[common.Hash]time.Time
+	seen      map[common.Hash]time.Time // included before being tracked
+	seenOrder [][]common.Hash           // hashes of seen per block, oldest first
+	latencies []time.Duration
+	stats     types.InclusionStats
+	next      uint64 // next block to scan
+
+	fetches sync.WaitGroup // receipt fetches running in the background
+	sem     chan struct{}  // bounds the concurrent receipt fetches
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func NewReceiptTracker(rpc interfaces.EthRpcRequester, pollInterval time.Duration) *ReceiptTracker {
+	return &ReceiptTracker{
+		rpc:          rpc,
+		pollInterval: pollInterval,
+		sent:         make(map[common.Hash]time.Time),
+		seen:         make(map[common.Hash]time.Time),
+		sem:          make(chan struct{}, rpcConcurrency),
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+}
+
+// Start scans every block after the current one until Finish is called. New
+// blocks are announced by heads, the tracker polls the head of the chain when
+// heads is nil or closed.
+func (rt *ReceiptTracker) Start(heads <-chan uint64) error {
+	head, err := BlockNumber(rt.rpc)
+	if err != nil {
+		return err
+	}
+	rt.next = head + 1
+	go rt.loop(heads)
+	return nil
+}
+
+// Track records the tx sent at sentAt.
+func (rt *ReceiptTracker) Track(txHash string, sentAt time.Time) {
+	hash := common.HexToHash(txHash)
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	rt.stats.Tracked++
+	if seenAt, ok := rt.seen[hash]; ok {
+		delete(rt.seen, hash)
+		rt.latencies = append(rt.latencies, seenAt.Sub(sentAt))
+		rt.stats.Included++
+		rt.fetchReceipts([]common.Hash{hash})
+		return
+	}
+	rt.sent[hash] = sentAt
+}
+
+// Finish waits until every tracked tx is included or the timeout expires,
+// stops scanning and returns the stats.
+func (rt *ReceiptTracker) Finish(timeout time.Duration) types.InclusionStats {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		rt.mu.Lock()
+		pending := len(rt.sent)
+		rt.mu.Unlock()
+		if pending == 0 {
+			break
+		}
+		time.Sleep(rt.pollInterval)
+	}
+	close(rt.stop)
+	<-rt.done
+	rt.fetches.Wait()
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	stats := rt.stats
+	stats.Pending = int64(len(rt.sent))
+	stats.Latency = types.SummarizeLatencies(rt.latencies)
+	return stats
+}
+
+func (rt *ReceiptTracker) loop(heads <-chan uint64) {
+	defer close(rt.done)
+	ticker := time.NewTicker(rt.pollInterval)
+	defer ticker.Stop()
+	tick := ticker.C
+	if heads != nil {
+		tick = nil
+	}
+	for {
+		select {
+		case <-rt.stop:
+			return
+		case head, ok := <-heads:
+			if !ok {
+				log.Warn().Msg("block subscription ended, polling blocks for receipts")
+				heads, tick = nil, ticker.C
+				continue
+			}
+			if err := rt.scanTo(head); err != nil {
+				log.Debug().Err(err).Msg("failed to scan blocks for receipts")
+			}
+		case <-tick:
+			if err := rt.poll(); err != nil {
+				log.Debug().Err(err).Msg("failed to poll blocks for receipts")
+			}
+		}
+	}
+}
+
+// poll scans every block produced since the last poll.
+func (rt *ReceiptTracker) poll() error {
+	head, err := BlockNumber(rt.rpc)
+	if err != nil {
+		return err
+	}
+	return rt.scanTo(head)
+}
+
+// scanTo scans every block up to head which wasn't scanned yet. Every block is
+// stamped with the time head was learned, so fetching the earlier blocks
+// doesn't delay the later ones. A block which can't be fetched is scanned
+// again on the next head.
+func (rt *ReceiptTracker) scanTo(head uint64) error {
+	seenAt := time.Now()
+	for ; rt.next <= head; rt.next++ {
+		block, err := fetchBlock(rt.rpc, rt.next)
+		if err != nil {
+			return err
+		}
+		rt.scan(&block, seenAt)
+	}
+	return nil
+}
+
+// scan matches the txs of the block with the tracked ones.
+func (rt *ReceiptTracker) scan(block *types.Block, seenAt time.Time) {
+	var included, unknown []common.Hash
+	rt.mu.Lock()
+	for _, hash := range block.Transactions {
+		sentAt, ok := rt.sent[hash]
+		if !ok {
+			rt.seen[hash] = seenAt
+			unknown = append(unknown, hash)
+			continue
+		}
+		delete(rt.sent, hash)
+		rt.latencies = append(rt.latencies, seenAt.Sub(sentAt))
+		rt.stats.Included++
+		included = append(included, hash)
+	}
+	rt.seenOrder = append(rt.seenOrder, unknown)
+	if len(rt.seenOrder) > seenBlocks {
+		for _, hash := range rt.seenOrder[0] {
+			delete(rt.seen, hash)
+		}
+		rt.seenOrder = rt.seenOrder[1:]
+	}
+	rt.mu.Unlock()
+	rt.fetchReceipts(included)
+}
+
+// fetchReceipts counts the reverted txs and the gas used by the included ones
+// in the background, so the next blocks are scanned meanwhile.
+func (rt *ReceiptTracker) fetchReceipts(hashes []common.Hash) {
+	for _, hash := range hashes {
+		rt.fetches.Add(1)
+		go func(hash common.Hash) {
+			rt.sem <- struct{}{}
+			defer func() { <-rt.sem; rt.fetches.Done() }()
+			var receipt *types.Receipt
+			if err := rt.rpc.RpcCall(&receipt, "eth_getTransactionReceipt", hash); err != nil || receipt == nil {
+				log.Debug().Err(err).Msgf("failed to fetch receipt of %s", hash.Hex())
+				return
+			}
+			rt.mu.Lock()
+			rt.stats.GasUsed += uint64(receipt.GasUsed)
+			if uint64(receipt.Status) == gethtypes.ReceiptStatusFailed {
+				rt.stats.Reverted++
+			}
+			rt.mu.Unlock()
+		}(hash)
+	}
+}
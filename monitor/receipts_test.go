@@ -0,0 +1,153 @@
+package monitor
+
+import (
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+)
+
+// fakeChain serves blocks, receipts, mempool txs, nonces and balances from memory.
+type fakeChain struct {
+	mu       sync.Mutex
+	blocks   []types.Block
+	receipts map[common.Hash]types.Receipt
+	pending  map[common.Hash]bool
+	nonces   map[common.Address]uint64
+	balances map[common.Address]*big.Int
+}
+
+func newFakeChain() *fakeChain {
+	return &fakeChain{
+		receipts: make(map[common.Hash]types.Receipt),
+		pending:  make(map[common.Hash]bool),
+		nonces:   make(map[common.Address]uint64),
+		balances: make(map[common.Address]*big.Int),
+	}
+}
+
+func (c *fakeChain) EthSendRawTransaction([]byte) error          { return nil }
+func (c *fakeChain) EthSendRawTransactionNoWaiting([]byte) error { return nil }
+func (c *fakeChain) EthPendingNonce(addr common.Address) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nonces[addr], nil
+}
+func (c *fakeChain) EthSendMultipleRawTransactions([][]byte, func(*sync.Mutex, int, types.SendTiming, error)) int64 {
+	return 0
+}
+
+func (c *fakeChain) RpcCall(result interface{}, method string, params ...interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var v interface{}
+	switch method {
+	case "eth_blockNumber":
+		v = hexutil.Uint64(len(c.blocks) - 1)
+	case "eth_getBlockByNumber":
+		if n := int(params[0].(hexutil.Uint64)); n < len(c.blocks) {
+			v = c.blocks[n]
+		}
+	case "eth_getTransactionReceipt":
+		if r, ok := c.receipts[params[0].(common.Hash)]; ok {
+			v = r
+		}
+	case "eth_getBalance":
+		v = (*hexutil.Big)(c.balances[params[0].(common.Address)])
+	case "eth_getTransactionByHash":
+		if c.pending[params[0].(common.Hash)] {
+			v = map[string]interface{}{"blockNumber": nil}
+		}
+	}
+	bz, _ := json.Marshal(v)
+	return json.Unmarshal(bz, result)
+}
+
+func (c *fakeChain) addBlock(receipts ...types.Receipt) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	block := types.Block{Number: hexutil.Uint64(len(c.blocks))}
+	for _, r := range receipts {
+		block.Transactions = append(block.Transactions, r.TxHash)
+		c.receipts[r.TxHash] = r
+	}
+	c.blocks = append(c.blocks, block)
+}
+
+func TestReceiptTracker(t *testing.T) {
+	chain := newFakeChain()
+	chain.addBlock()
+	rt := NewReceiptTracker(chain, 10*time.Millisecond)
+	require.NoError(t, rt.Start(nil))
+
+	ok, reverted, pending := common.HexToHash("0x1"), common.HexToHash("0x2"), common.HexToHash("0x3")
+	sentAt := time.Now()
+	rt.Track(ok.Hex(), sentAt)
+	rt.Track(pending.Hex(), sentAt)
+	chain.addBlock(types.Receipt{TxHash: ok, Status: 1, GasUsed: 21000})
+	// included before being tracked
+	chain.addBlock(types.Receipt{TxHash: reverted, Status: 0, GasUsed: 30000})
+	time.Sleep(50 * time.Millisecond)
+	rt.Track(reverted.Hex(), sentAt)
+
+	stats := rt.Finish(50 * time.Millisecond)
+	require.Equal(t, int64(3), stats.Tracked)
+	require.Equal(t, int64(2), stats.Included)
+	require.Equal(t, int64(1), stats.Reverted)
+	require.Equal(t, int64(1), stats.Pending)
+	require.Equal(t, uint64(51000), stats.GasUsed)
+	require.Equal(t, 2, stats.Latency.Count)
+}
+
+func TestReceiptTrackerScan(t *testing.T) {
+	chain := newFakeChain()
+	chain.addBlock()
+	rt := NewReceiptTracker(chain, time.Hour)
+	rt.next = 1
+
+	first, last := common.HexToHash("0x1"), common.HexToHash("0x2")
+	sentAt := time.Now()
+	rt.Track(first.Hex(), sentAt)
+	rt.Track(last.Hex(), sentAt)
+	chain.addBlock(types.Receipt{TxHash: first, Status: 1})
+	for i := 0; i < seenBlocks+8; i++ {
+		// txs of someone else
+		chain.addBlock(types.Receipt{TxHash: common.BigToHash(big.NewInt(int64(100 + i))), Status: 1})
+	}
+	chain.addBlock(types.Receipt{TxHash: last, Status: 1})
+	require.NoError(t, rt.scanTo(uint64(len(chain.blocks)-1)))
+	rt.fetches.Wait()
+
+	// blocks found by the same scan share the time they were learned
+	require.Len(t, rt.latencies, 2)
+	require.Equal(t, rt.latencies[0], rt.latencies[1])
+	// txs which aren't ours are only kept for the last blocks, the very last
+	// one only holding ours
+	require.Len(t, rt.seen, seenBlocks-1)
+	require.Equal(t, int64(2), rt.stats.Included)
+}
+
+func TestReceiptTrackerMissingBlock(t *testing.T) {
+	chain := newFakeChain()
+	chain.addBlock()
+	rt := NewReceiptTracker(chain, time.Hour)
+	rt.next = 1
+
+	tx := common.HexToHash("0x1")
+	rt.Track(tx.Hex(), time.Now())
+	// a lagging endpoint doesn't have the head another one reported
+	require.Error(t, rt.scanTo(1))
+	require.Equal(t, uint64(1), rt.next)
+
+	chain.addBlock(types.Receipt{TxHash: tx, Status: 1})
+	require.NoError(t, rt.scanTo(1))
+	rt.fetches.Wait()
+	require.Equal(t, int64(1), rt.stats.Included)
+}
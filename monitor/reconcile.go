@@ -0,0 +1,137 @@
+package monitor
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/rs/zerolog/log"
+
+	"loadtester/interfaces"
+	"loadtester/types"
+)
+
+// SentTxs collects the txs accepted by the node during a run.
+type SentTxs struct {
+	mu  sync.Mutex
+	txs []types.SentTx
+}
+
+func (s *SentTxs) Add(txHash string, from common.Address, nonce uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.txs = append(s.txs, types.SentTx{Hash: common.HexToHash(txHash), From: from, Nonce: nonce})
+}
+
+func (s *SentTxs) All() []types.SentTx {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]types.SentTx(nil), s.txs...)
+}
+
+// Reconcile classifies every tx against the chain state: included, reverted,
+// pending in the mempool, replaced by another tx of the same nonce or dropped.
+func Reconcile(rpc interfaces.EthRpcRequester, txs []types.SentTx) types.Reconciliation {
+	nonces := newNonceCache(rpc)
+	states := make([]types.TxState, len(txs))
+	sem := make(chan struct{}, rpcConcurrency)
+	wg := sync.WaitGroup{}
+	for i := range txs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+			states[i] = txState(rpc, nonces, txs[i])
+		}(i)
+	}
+	wg.Wait()
+
+	rec := types.Reconciliation{Counts: make(map[types.TxState]int64)}
+	for i, state := range states {
+		rec.Counts[state]++
+		switch state {
+		case types.TxPending:
+			rec.Pending = append(rec.Pending, txs[i])
+		case types.TxDropped:
+			rec.Dropped = append(rec.Dropped, txs[i])
+		}
+	}
+	for _, list := range [][]types.SentTx{rec.Pending, rec.Dropped} {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].From != list[j].From {
+				return list[i].From.Hex() < list[j].From.Hex()
+			}
+			return list[i].Nonce < list[j].Nonce
+		})
+	}
+	return rec
+}
+
+func txState(rpc interfaces.EthRpcRequester, nonces *nonceCache, tx types.SentTx) types.TxState {
+	var receipt *types.Receipt
+	if err := rpc.RpcCall(&receipt, "eth_getTransactionReceipt", tx.Hash); err != nil {
+		log.Debug().Err(err).Msgf("failed to fetch receipt of %s", tx.Hash.Hex())
+		return types.TxUnknown
+	}
+	if receipt != nil {
+		if uint64(receipt.Status) == gethtypes.ReceiptStatusFailed {
+			return types.TxIncludedReverted
+		}
+		return types.TxIncluded
+	}
+
+	var known *struct {
+		BlockNumber *hexutil.Uint64 `json:"blockNumber"`
+	}
+	if err := rpc.RpcCall(&known, "eth_getTransactionByHash", tx.Hash); err != nil {
+		log.Debug().Err(err).Msgf("failed to fetch tx %s", tx.Hash.Hex())
+		return types.TxUnknown
+	}
+	if known != nil {
+		// included in the meantime if it has a block number
+		if known.BlockNumber != nil {
+			return types.TxIncluded
+		}
+		return types.TxPending
+	}
+
+	nonce, err := nonces.get(tx.From)
+	if err != nil {
+		log.Debug().Err(err).Msgf("failed to fetch nonce of %s", tx.From.Hex())
+		return types.TxUnknown
+	}
+	if nonce > tx.Nonce {
+		return types.TxReplaced
+	}
+	return types.TxDropped
+}
+
+// nonceCache queries the on-chain nonce of each sender once.
+type nonceCache struct {
+	rpc    interfaces.EthRpcRequester
+	mu     sync.Mutex
+	nonces map[common.Address]uint64
+}
+
+func newNonceCache(rpc interfaces.EthRpcRequester) *nonceCache {
+	return &nonceCache{rpc: rpc, nonces: make(map[common.Address]uint64)}
+}
+
+func (c *nonceCache) get(addr common.Address) (uint64, error) {
+	c.mu.Lock()
+	nonce, ok := c.nonces[addr]
+	c.mu.Unlock()
+	if ok {
+		return nonce, nil
+	}
+	nonce, err := c.rpc.EthPendingNonce(addr)
+	if err != nil {
+		return 0, err
+	}
+	c.mu.Lock()
+	c.nonces[addr] = nonce
+	c.mu.Unlock()
+	return nonce, nil
+}
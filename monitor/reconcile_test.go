@@ -0,0 +1,38 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+)
+
+func TestReconcile(t *testing.T) {
+	chain := newFakeChain()
+	sender := common.HexToAddress("0xa")
+	included, reverted, pending, replaced, dropped :=
+		common.HexToHash("0x1"), common.HexToHash("0x2"), common.HexToHash("0x3"), common.HexToHash("0x4"), common.HexToHash("0x5")
+	chain.addBlock(types.Receipt{TxHash: included, Status: 1}, types.Receipt{TxHash: reverted, Status: 0})
+	chain.pending[pending] = true
+	chain.nonces[sender] = 4 // nonce 3 is used, nonce 4 isn't
+
+	sent := &SentTxs{}
+	sent.Add(included.Hex(), sender, 0)
+	sent.Add(reverted.Hex(), sender, 1)
+	sent.Add(pending.Hex(), sender, 5)
+	sent.Add(replaced.Hex(), sender, 3)
+	sent.Add(dropped.Hex(), sender, 4)
+
+	rec := Reconcile(chain, sent.All())
+	require.Equal(t, map[types.TxState]int64{
+		types.TxIncluded:         1,
+		types.TxIncludedReverted: 1,
+		types.TxPending:          1,
+		types.TxReplaced:         1,
+		types.TxDropped:          1,
+	}, rec.Counts)
+	require.Equal(t, []types.SentTx{{Hash: pending, From: sender, Nonce: 5}}, rec.Pending)
+	require.Equal(t, []types.SentTx{{Hash: dropped, From: sender, Nonce: 4}}, rec.Dropped)
+}
@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	"loadtester/interfaces"
+	"loadtester/types"
+)
+
+// BlockNumber returns the current head of the chain.
+func BlockNumber(rpc interfaces.EthRpcRequester) (uint64, error) {
+	var head hexutil.Uint64
+	if err := rpc.RpcCall(&head, "eth_blockNumber"); err != nil {
+		return 0, err
+	}
+	return uint64(head), nil
+}
+
+// FetchBlocks returns the blocks from, to, both included, without full txs.
+func FetchBlocks(rpc interfaces.EthRpcRequester, from, to uint64) ([]types.Block, error) {
+	blocks := make([]types.Block, to-from+1)
+	err := forEachBlock(from, to, func(n uint64) error {
+		block, err := fetchBlock(rpc, n)
+		blocks[n-from] = block
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// fetchBlock returns block n. A node which doesn't have the block yet, like a
+// lagging endpoint of the pool, answers null, which is an error.
+func fetchBlock(rpc interfaces.EthRpcRequester, n uint64) (types.Block, error) {
+	var block *types.Block
+	if err := rpc.RpcCall(&block, "eth_getBlockByNumber", hexutil.Uint64(n), false); err != nil {
+		return types.Block{}, err
+	}
+	if block == nil {
+		return types.Block{}, errors.Errorf("block %d not found", n)
+	}
+	return *block, nil
+}
+
+// BlockSeries returns blocks[1:] as a time series timestamped with the block
+// timestamps, the first block marking the start of the range.
+func BlockSeries(blocks []types.Block) []types.BlockSample {
+	if len(blocks) < 2 {
+		return nil
+	}
+	series := make([]types.BlockSample, 0, len(blocks)-1)
+	for i, b := range blocks[1:] {
+		series = append(series, types.BlockSample{
+			Number:    uint64(b.Number),
+			ArrivedAt: time.Unix(int64(b.Timestamp), 0),
+			Interval:  time.Duration(b.Timestamp-blocks[i].Timestamp) * time.Second,
+			Txs:       len(b.Transactions),
+			GasUsed:   uint64(b.GasUsed),
+			GasLimit:  uint64(b.GasLimit),
+		})
+	}
+	return series
+}
+
+// forEachBlock calls fetch for every block from, to concurrently and returns
+// the first error.
+func forEachBlock(from, to uint64, fetch func(n uint64) error) error {
+	var firstErr error
+	mu := sync.Mutex{}
+	sem := make(chan struct{}, rpcConcurrency)
+	wg := sync.WaitGroup{}
+	for n := from; n <= to; n++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(n uint64) {
+			defer func() { <-sem; wg.Done() }()
+			if err := fetch(n); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = errors.Wrapf(err, "block %d", n)
+				}
+				mu.Unlock()
+			}
+		}(n)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// Throughput computes the on-chain throughput of blocks[1:], the first block
+// only marks the start of the range.
+func Throughput(blocks []types.Block, ours map[string]bool) types.ThroughputStats {
+	if len(blocks) < 2 {
+		return types.ThroughputStats{}
+	}
+	first, last := blocks[0], blocks[len(blocks)-1]
+	stats := types.ThroughputStats{
+		FromBlock: uint64(first.Number),
+		ToBlock:   uint64(last.Number),
+		Blocks:    len(blocks) - 1,
+		Duration:  time.Duration(last.Timestamp-first.Timestamp) * time.Second,
+	}
+	for _, block := range blocks[1:] {
+		stats.Txs += int64(len(block.Transactions))
+		stats.GasUsed += uint64(block.GasUsed)
+		for _, hash := range block.Transactions {
+			if ours[hash.Hex()] {
+				stats.OurTxs++
+			}
+		}
+	}
+	if stats.Txs > 0 {
+		stats.OurFraction = float64(stats.OurTxs) / float64(stats.Txs)
+	}
+	if stats.Duration > 0 {
+		stats.Tps = float64(stats.Txs) / stats.Duration.Seconds()
+	}
+	stats.TxsPerBlock = float64(stats.Txs) / float64(stats.Blocks)
+	stats.GasPerBlock = float64(stats.GasUsed) / float64(stats.Blocks)
+	return stats
+}
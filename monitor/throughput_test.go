@@ -0,0 +1,51 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+)
+
+func TestThroughput(t *testing.T) {
+	ours, other := common.HexToHash("0x1"), common.HexToHash("0x2")
+	blocks := []types.Block{
+		{Number: 10, Timestamp: 100, Transactions: []common.Hash{other}}, // start of the range, not counted
+		{Number: 11, Timestamp: 102, GasUsed: 42000, Transactions: []common.Hash{ours, other}},
+		{Number: 12, Timestamp: 104, GasUsed: 21000, Transactions: []common.Hash{other}},
+	}
+	stats := Throughput(blocks, map[string]bool{ours.Hex(): true})
+	require.Equal(t, uint64(10), stats.FromBlock)
+	require.Equal(t, uint64(12), stats.ToBlock)
+	require.Equal(t, 2, stats.Blocks)
+	require.Equal(t, int64(3), stats.Txs)
+	require.Equal(t, int64(1), stats.OurTxs)
+	require.Equal(t, 4*time.Second, stats.Duration)
+	require.InDelta(t, 0.75, stats.Tps, 1e-9)
+	require.InDelta(t, 1.5, stats.TxsPerBlock, 1e-9)
+	require.InDelta(t, 31500, stats.GasPerBlock, 1e-9)
+
+	series := BlockSeries(blocks)
+	require.Len(t, series, 2)
+	require.Equal(t, uint64(11), series[0].Number)
+	require.Equal(t, time.Unix(102, 0), series[0].ArrivedAt)
+	require.Equal(t, 2*time.Second, series[0].Interval)
+	require.Equal(t, 2, series[0].Txs)
+	require.Equal(t, uint64(42000), series[0].GasUsed)
+}
+
+func TestFetchBlocksMissing(t *testing.T) {
+	chain := newFakeChain()
+	chain.addBlock()
+	chain.addBlock()
+	blocks, err := FetchBlocks(chain, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+
+	// null for a block the node doesn't have yet
+	_, err = FetchBlocks(chain, 0, 2)
+	require.Error(t, err)
+}
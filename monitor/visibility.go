@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"loadtester/interfaces"
+	"loadtester/types"
+)
+
+const (
+	visibilityPollInterval = 50 * time.Millisecond
+	visibilityTimeout      = 30 * time.Second
+)
+
+// VisibilityProber measures, for a sample of the sent txs, how long it takes
+// until the node returns them from eth_getTransactionByHash.
+type VisibilityProber struct {
+	rpc        interfaces.EthRpcRequester
+	sampleRate float64
+
+	wg        sync.WaitGroup
+	mu        sync.Mutex
+	latencies []time.Duration
+	timedOut  int64
+}
+
+func NewVisibilityProber(rpc interfaces.EthRpcRequester, sampleRate float64) *VisibilityProber {
+	return &VisibilityProber{rpc: rpc, sampleRate: sampleRate}
+}
+
+// Probe polls the tx sent at sentAt in the background if it is sampled.
+func (vp *VisibilityProber) Probe(txHash string, sentAt time.Time) {
+	if rand.Float64() >= vp.sampleRate {
+		return
+	}
+	hash := common.HexToHash(txHash)
+	vp.wg.Add(1)
+	go func() {
+		defer vp.wg.Done()
+		deadline := sentAt.Add(visibilityTimeout)
+		for time.Now().Before(deadline) {
+			var tx *struct {
+				Hash common.Hash `json:"hash"`
+			}
+			if err := vp.rpc.RpcCall(&tx, "eth_getTransactionByHash", hash); err == nil && tx != nil {
+				vp.mu.Lock()
+				vp.latencies = append(vp.latencies, time.Since(sentAt))
+				vp.mu.Unlock()
+				return
+			}
+			time.Sleep(visibilityPollInterval)
+		}
+		vp.mu.Lock()
+		vp.timedOut++
+		vp.mu.Unlock()
+	}()
+}
+
+// Finish waits for the running probes and returns the visibility latencies
+// along with the number of sampled txs which never became visible.
+func (vp *VisibilityProber) Finish() (types.LatencySummary, int64) {
+	vp.wg.Wait()
+	vp.mu.Lock()
+	defer vp.mu.Unlock()
+	return types.SummarizeLatencies(vp.latencies), vp.timedOut
+}
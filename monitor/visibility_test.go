@@ -0,0 +1,25 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisibilityProber(t *testing.T) {
+	chain := newFakeChain()
+	hash := common.HexToHash("0x1")
+	vp := NewVisibilityProber(chain, 1)
+	vp.Probe(hash.Hex(), time.Now())
+	time.Sleep(2 * visibilityPollInterval)
+	chain.mu.Lock()
+	chain.pending[hash] = true
+	chain.mu.Unlock()
+
+	latency, invisible := vp.Finish()
+	require.Zero(t, invisible)
+	require.Equal(t, 1, latency.Count)
+	require.GreaterOrEqual(t, latency.Min, 2*visibilityPollInterval)
+}
@@ -0,0 +1,24 @@
+package report
+
+import (
+	"github.com/rs/zerolog/log"
+)
+
+// LogAccounts logs the senders whose on-chain nonce or balance doesn't match
+// the local state.
+func (r *Report) LogAccounts() {
+	v := r.Accounts
+	if v == nil {
+		return
+	}
+	log.Info().Msgf("account verification: checked:%d, failed:%d, nonceDrifts:%d, balanceMismatches:%d",
+		v.Checked, v.Failed, v.NonceDrifts, v.BalanceOffs)
+	for i, m := range v.Mismatches {
+		if i == maxLogged {
+			log.Info().Msgf("  ... %d more mismatches", len(v.Mismatches)-maxLogged)
+			return
+		}
+		log.Warn().Msgf("  %s: localNonce:%d, onchainNonce:%d, spend:%s, expected:[%s, %s]",
+			m.Address.Hex(), m.LocalNonce, m.OnchainNonce, m.Spend, m.ExpectedMin, m.ExpectedMax)
+	}
+}
@@ -0,0 +1,138 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"math"
+	"strings"
+)
+
+const (
+	chartWidth  = 860
+	chartHeight = 260
+	chartLeft   = 70
+	chartRight  = 20
+	chartTop    = 30
+	chartBottom = 40
+	chartTicks  = 5
+)
+
+// chartColors are assigned to the series of a chart in order.
+var chartColors = []string{"#1f77b4", "#d62728", "#2ca02c", "#ff7f0e", "#9467bd"}
+
+type point struct {
+	X, Y float64
+}
+
+type series struct {
+	Name   string
+	Points []point
+}
+
+// lineChart draws the series as an inline svg, y starting at zero.
+func lineChart(title, xLabel, yLabel string, lines []series) template.HTML {
+	var maxX, maxY float64
+	minX := math.Inf(1)
+	for _, s := range lines {
+		for _, p := range s.Points {
+			minX, maxX, maxY = math.Min(minX, p.X), math.Max(maxX, p.X), math.Max(maxY, p.Y)
+		}
+	}
+	if math.IsInf(minX, 1) {
+		return noData(title)
+	}
+	if maxX == minX {
+		maxX = minX + 1
+	}
+	if maxY == 0 {
+		maxY = 1
+	}
+	plotW := float64(chartWidth - chartLeft - chartRight)
+	plotH := float64(chartHeight - chartTop - chartBottom)
+	x := func(v float64) float64 { return chartLeft + (v-minX)/(maxX-minX)*plotW }
+	y := func(v float64) float64 { return chartTop + plotH - v/maxY*plotH }
+
+	b := &strings.Builder{}
+	openSvg(b, title)
+	for i := 0; i <= chartTicks; i++ {
+		yv := maxY * float64(i) / chartTicks
+		xv := minX + (maxX-minX)*float64(i)/chartTicks
+		fmt.Fprintf(b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#eee"/>`, chartLeft, y(yv), chartWidth-chartRight, y(yv))
+		fmt.Fprintf(b, `<text x="%d" y="%.1f" text-anchor="end" dominant-baseline="middle">%s</text>`, chartLeft-6, y(yv), formatTick(yv))
+		fmt.Fprintf(b, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`, x(xv), chartHeight-chartBottom+16, formatTick(xv))
+	}
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle">%s</text>`,
+		chartLeft+int(plotW)/2, chartHeight-4, html.EscapeString(xLabel))
+	fmt.Fprintf(b, `<text x="14" y="%d" text-anchor="middle" transform="rotate(-90 14 %d)">%s</text>`,
+		chartTop+int(plotH)/2, chartTop+int(plotH)/2, html.EscapeString(yLabel))
+	for i, s := range lines {
+		color := chartColors[i%len(chartColors)]
+		coords := make([]string, len(s.Points))
+		for j, p := range s.Points {
+			coords[j] = fmt.Sprintf("%.1f,%.1f", x(p.X), y(p.Y))
+		}
+		fmt.Fprintf(b, `<polyline fill="none" stroke="%s" stroke-width="1.5" points="%s"/>`, color, strings.Join(coords, " "))
+		legendX := chartWidth - chartRight - (len(lines)-i)*150
+		fmt.Fprintf(b, `<rect x="%d" y="7" width="10" height="10" fill="%s"/>`, legendX, color)
+		fmt.Fprintf(b, `<text x="%d" y="16">%s</text>`, legendX+14, html.EscapeString(s.Name))
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+// barChart draws one horizontal bar per label as an inline svg.
+func barChart(title string, labels []string, values []float64) template.HTML {
+	if len(labels) == 0 {
+		return noData(title)
+	}
+	maxV := 0.0
+	for _, v := range values {
+		maxV = math.Max(maxV, v)
+	}
+	if maxV == 0 {
+		maxV = 1
+	}
+	const barH, gap, labelW = 20, 8, 160
+	height := chartTop + len(labels)*(barH+gap) + 10
+	plotW := float64(chartWidth - labelW - chartRight - 60)
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="11">`, chartWidth, height)
+	fmt.Fprintf(b, `<text x="%d" y="16" font-size="14" font-weight="bold">%s</text>`, 10, html.EscapeString(title))
+	for i, label := range labels {
+		top := chartTop + i*(barH+gap)
+		w := values[i] / maxV * plotW
+		fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="end" dominant-baseline="middle">%s</text>`, labelW-6, top+barH/2, html.EscapeString(label))
+		fmt.Fprintf(b, `<rect x="%d" y="%d" width="%.1f" height="%d" fill="%s"/>`, labelW, top, w, barH, chartColors[1])
+		fmt.Fprintf(b, `<text x="%.1f" y="%d" dominant-baseline="middle">%s</text>`, float64(labelW)+w+6, top+barH/2, formatTick(values[i]))
+	}
+	b.WriteString(`</svg>`)
+	return template.HTML(b.String())
+}
+
+func openSvg(b *strings.Builder, title string) {
+	fmt.Fprintf(b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="11">`, chartWidth, chartHeight)
+	fmt.Fprintf(b, `<text x="%d" y="16" font-size="14" font-weight="bold">%s</text>`, 10, html.EscapeString(title))
+}
+
+func noData(title string) template.HTML {
+	b := &strings.Builder{}
+	openSvg(b, title)
+	fmt.Fprintf(b, `<text x="%d" y="%d" text-anchor="middle" fill="#888">no data</text></svg>`, chartWidth/2, chartHeight/2)
+	return template.HTML(b.String())
+}
+
+// formatTick keeps axis labels short.
+func formatTick(v float64) string {
+	switch {
+	case v == 0:
+		return "0"
+	case math.Abs(v) >= 1e6:
+		return fmt.Sprintf("%.3gM", v/1e6)
+	case math.Abs(v) >= 1e3:
+		return fmt.Sprintf("%.3gk", v/1e3)
+	default:
+		return fmt.Sprintf("%.3g", v)
+	}
+}
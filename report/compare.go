@@ -0,0 +1,176 @@
+package report
+
+import (
+	"math"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"loadtester/types"
+)
+
+// Thresholds bounds how much a candidate run may regress from its baseline.
+type Thresholds struct {
+	// ThroughputDrop is the tolerated relative throughput drop, in percent
+	ThroughputDrop float64
+	// LatencyIncrease is the tolerated relative latency increase, in percent
+	LatencyIncrease float64
+	// RateIncrease is the tolerated increase of the error and pending rates,
+	// in percentage points
+	RateIncrease float64
+}
+
+type metricKind int
+
+const (
+	// higher is better, compared relatively
+	kindThroughput metricKind = iota
+	// lower is better, compared relatively
+	kindLatency
+	// a percentage, lower is better, compared in percentage points
+	kindRate
+)
+
+// Delta is the change of a single metric between two runs. Change is relative,
+// in percent, except for rates where it is given in percentage points.
+type Delta struct {
+	Metric    string  `json:"metric"`
+	Unit      string  `json:"unit"`
+	Baseline  float64 `json:"baseline"`
+	Candidate float64 `json:"candidate"`
+	Change    float64 `json:"change"`
+	Regressed bool    `json:"regressed"`
+}
+
+type metric struct {
+	name  string
+	unit  string
+	kind  metricKind
+	value func(r *Report) (float64, bool)
+}
+
+var metrics = []metric{
+	// fire-and-forget txs are only written, they count as sent
+	{"client tpu", "txs", kindThroughput, func(r *Report) (float64, bool) {
+		return r.perTimeUnit(float64(int64(r.Succeeded) + r.written())), r.TimeSpent > 0 && r.TimeUnit > 0
+	}},
+	{"on-chain tps", "txs", kindThroughput, func(r *Report) (float64, bool) {
+		if r.Throughput == nil || r.Throughput.Duration == 0 {
+			return 0, false
+		}
+		return r.Throughput.Tps, true
+	}},
+	{"error rate", "%", kindRate, func(r *Report) (float64, bool) {
+		total := float64(int64(r.Succeeded) + r.written() + r.Failed)
+		if total == 0 {
+			return 0, false
+		}
+		return 100 * float64(r.Failed) / total, true
+	}},
+	{"pending rate", "%", kindRate, func(r *Report) (float64, bool) {
+		if r.Inclusion == nil || r.Inclusion.Tracked == 0 {
+			return 0, false
+		}
+		return 100 * float64(r.Inclusion.Pending) / float64(r.Inclusion.Tracked), true
+	}},
+	{"send latency p50", "ms", kindLatency, sendLatency(func(l types.LatencySummary) time.Duration { return l.P50 })},
+	{"send latency p99", "ms", kindLatency, sendLatency(func(l types.LatencySummary) time.Duration { return l.P99 })},
+	{"inclusion latency p50", "ms", kindLatency, stageLatency(types.StageInclusion, func(l types.LatencySummary) time.Duration { return l.P50 })},
+	{"inclusion latency p99", "ms", kindLatency, stageLatency(types.StageInclusion, func(l types.LatencySummary) time.Duration { return l.P99 })},
+}
+
+// written returns how many txs were written in fire-and-forget mode.
+func (r *Report) written() int64 {
+	var written int64
+	for _, i := range r.Intervals {
+		written += i.Written
+	}
+	return written
+}
+
+func sendLatency(percentile func(types.LatencySummary) time.Duration) func(r *Report) (float64, bool) {
+	return func(r *Report) (float64, bool) {
+		if r.Latency == nil || r.Latency.Total.Count == 0 {
+			return 0, false
+		}
+		return millis64(percentile(r.Latency.Total)), true
+	}
+}
+
+func stageLatency(stage types.Stage, percentile func(types.LatencySummary) time.Duration) func(r *Report) (float64, bool) {
+	return func(r *Report) (float64, bool) {
+		l, ok := r.Stages[stage]
+		if !ok || l.Count == 0 {
+			return 0, false
+		}
+		return millis64(percentile(l)), true
+	}
+}
+
+// Compare diffs the metrics set in both runs and flags the ones regressing
+// beyond thresholds.
+func Compare(baseline, candidate *Report, thresholds Thresholds) []Delta {
+	var deltas []Delta
+	for _, m := range metrics {
+		base, ok := m.value(baseline)
+		if !ok {
+			continue
+		}
+		cand, ok := m.value(candidate)
+		if !ok {
+			continue
+		}
+		d := Delta{Metric: m.name, Unit: m.unit, Baseline: base, Candidate: cand}
+		switch m.kind {
+		case kindThroughput:
+			d.Change = relativeChange(base, cand)
+			d.Regressed = -d.Change > thresholds.ThroughputDrop
+		case kindLatency:
+			d.Change = relativeChange(base, cand)
+			d.Regressed = d.Change > thresholds.LatencyIncrease
+		case kindRate:
+			d.Change = cand - base
+			d.Regressed = d.Change > thresholds.RateIncrease
+		}
+		deltas = append(deltas, d)
+	}
+	return deltas
+}
+
+// relativeChange returns the change from base to cand in percent, a change
+// from 0 counting as +inf.
+func relativeChange(base, cand float64) float64 {
+	if base == 0 {
+		if cand == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return 100 * (cand - base) / base
+}
+
+// Regressions returns how many deltas regressed.
+func Regressions(deltas []Delta) int {
+	n := 0
+	for _, d := range deltas {
+		if d.Regressed {
+			n++
+		}
+	}
+	return n
+}
+
+// LogComparison logs every delta, regressions as warnings.
+func LogComparison(deltas []Delta) {
+	for _, d := range deltas {
+		change := "%+.2f%%"
+		if d.Unit == "%" {
+			change = "%+.2fpp"
+		}
+		event := log.Info()
+		if d.Regressed {
+			event = log.Warn()
+		}
+		event.Msgf("%-22s %12.2f -> %12.2f %-3s "+change, d.Metric, d.Baseline, d.Candidate, d.Unit, d.Change)
+	}
+}
@@ -0,0 +1,67 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+)
+
+func TestCompare(t *testing.T) {
+	baseline := &Report{
+		TimeUnit:   time.Second,
+		TimeSpent:  10 * time.Second,
+		Succeeded:  1000,
+		Failed:     0,
+		Throughput: &types.ThroughputStats{Duration: 10 * time.Second, Tps: 100},
+		Latency:    &types.RequestLatency{Total: types.LatencySummary{Count: 1000, P50: 10 * time.Millisecond, P99: 50 * time.Millisecond}},
+	}
+	candidate := &Report{
+		TimeUnit:   time.Second,
+		TimeSpent:  10 * time.Second,
+		Succeeded:  980,
+		Failed:     20,
+		Throughput: &types.ThroughputStats{Duration: 10 * time.Second, Tps: 90},
+		Latency:    &types.RequestLatency{Total: types.LatencySummary{Count: 1000, P50: 10 * time.Millisecond, P99: 60 * time.Millisecond}},
+	}
+	deltas := Compare(baseline, candidate, Thresholds{ThroughputDrop: 5, LatencyIncrease: 10, RateIncrease: 1})
+
+	byMetric := make(map[string]Delta)
+	for _, d := range deltas {
+		byMetric[d.Metric] = d
+	}
+	// no inclusion was tracked by either run
+	require.Len(t, deltas, 5)
+	require.False(t, byMetric["client tpu"].Regressed) // -2%
+	require.InDelta(t, -2, byMetric["client tpu"].Change, 1e-9)
+	require.True(t, byMetric["on-chain tps"].Regressed) // -10%
+	require.True(t, byMetric["error rate"].Regressed)   // +2pp
+	require.InDelta(t, 2, byMetric["error rate"].Change, 1e-9)
+	require.False(t, byMetric["send latency p50"].Regressed)
+	require.True(t, byMetric["send latency p99"].Regressed) // +20%
+	require.Equal(t, 3, Regressions(deltas))
+
+	require.Zero(t, Regressions(Compare(baseline, baseline, Thresholds{})))
+}
+
+func TestCompareFireAndForget(t *testing.T) {
+	run := func(written, failed int64) *Report {
+		return &Report{
+			TimeUnit:  time.Second,
+			TimeSpent: 10 * time.Second,
+			Failed:    failed,
+			Intervals: []types.Interval{{Written: written / 2}, {Written: written - written/2}},
+		}
+	}
+	deltas := Compare(run(1000, 0), run(999, 1), Thresholds{ThroughputDrop: 5, RateIncrease: 1})
+	byMetric := make(map[string]Delta)
+	for _, d := range deltas {
+		byMetric[d.Metric] = d
+	}
+	// written txs count as sent, a single failure isn't a 100% error rate
+	require.InDelta(t, -0.1, byMetric["client tpu"].Change, 1e-9)
+	require.InDelta(t, 0.1, byMetric["error rate"].Candidate, 1e-9)
+	require.Zero(t, Regressions(deltas))
+}
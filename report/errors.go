@@ -0,0 +1,37 @@
+package report
+
+import (
+	"sync"
+
+	"loadtester/types"
+)
+
+// ErrorCounts counts failed submissions per error category. It is safe for
+// concurrent use.
+type ErrorCounts struct {
+	mu     sync.Mutex
+	counts map[types.ErrorCategory]int64
+}
+
+func NewErrorCounts() *ErrorCounts {
+	return &ErrorCounts{counts: make(map[types.ErrorCategory]int64)}
+}
+
+// Add records a failed submission of the given error.
+func (c *ErrorCounts) Add(err error) {
+	category := types.CategoryOf(err)
+	c.mu.Lock()
+	c.counts[category]++
+	c.mu.Unlock()
+}
+
+// Snapshot returns a copy of the current counts.
+func (c *ErrorCounts) Snapshot() map[types.ErrorCategory]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	snapshot := make(map[types.ErrorCategory]int64, len(c.counts))
+	for k, v := range c.counts {
+		snapshot[k] = v
+	}
+	return snapshot
+}
@@ -0,0 +1,25 @@
+package report
+
+import (
+	"github.com/rs/zerolog/log"
+)
+
+// LogFeeOrdering logs how well the inclusion order of the txs followed their
+// gas price.
+func (r *Report) LogFeeOrdering() {
+	o := r.FeeOrdering
+	if o == nil {
+		return
+	}
+	log.Info().Msgf("fee ordering: txs:%d, correlation:%.3f, inversions:%d/%d (%.2f%%)",
+		o.Txs, o.Correlation, o.Inversions, o.Pairs, percent(o.Inversions, o.Pairs))
+	log.Info().Msgf("  within blocks: blocks:%d, correlation:%.3f, inversions:%d/%d (%.2f%%)",
+		o.Blocks, o.BlockCorrelation, o.BlockInversions, o.BlockPairs, percent(o.BlockInversions, o.BlockPairs))
+}
+
+func percent(n, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) * 100 / float64(total)
+}
@@ -0,0 +1,136 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"loadtester/types"
+)
+
+// RunFile is the machine-readable result of a run. Durations are given in
+// nanoseconds.
+type RunFile struct {
+	Version string `json:"version"`
+	Command string `json:"command"`
+	// Config is the effective config of the run, keyed like config.toml
+	Config map[string]interface{} `json:"config"`
+	*Report
+}
+
+// WriteRunFile writes the report as json into dir, and its time series as csv
+// files next to it if withCsv is set. It returns the paths of the written files.
+func WriteRunFile(dir string, run RunFile, withCsv bool) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	base := filepath.Join(dir, fmt.Sprintf("%s-%s", run.Command, run.StartedAt.Format("20060102-150405")))
+	b, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode the run file")
+	}
+	if err := os.WriteFile(base+".json", b, 0o644); err != nil {
+		return nil, err
+	}
+	paths := []string{base + ".json"}
+	if !withCsv {
+		return paths, nil
+	}
+	series := run.series()
+	for _, name := range []string{"intervals", "mempool", "blocks", "block_series"} {
+		rows := series[name]
+		if len(rows) < 2 {
+			continue // header only
+		}
+		path := fmt.Sprintf("%s-%s.csv", base, name)
+		if err := writeCsv(path, rows); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// ReadRunFile reads a run file written by WriteRunFile.
+func ReadRunFile(path string) (RunFile, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return RunFile{}, err
+	}
+	run := RunFile{Report: &Report{}}
+	if err := json.Unmarshal(b, &run); err != nil {
+		return RunFile{}, errors.Wrapf(err, "failed to decode the run file %s", path)
+	}
+	return run, nil
+}
+
+// series returns the rows of every time series of the report, headers first.
+// Latencies are given in milliseconds.
+func (r *Report) series() map[string][][]string {
+	intervals := [][]string{{"start", "elapsed_s", "sent", "failed",
+		"latency_count", "latency_mean_ms", "latency_p50_ms", "latency_p90_ms", "latency_p99_ms", "latency_p99_9_ms", "latency_max_ms",
+		"written"}}
+	for _, i := range r.Intervals {
+		l := i.Latency
+		intervals = append(intervals, []string{
+			i.Start.Format(time.RFC3339Nano), seconds(i.Start.Sub(r.StartedAt)), itoa(int64(i.Sent)), itoa(i.Failed),
+			itoa(int64(l.Count)), millis(l.Mean), millis(l.P50), millis(l.P90), millis(l.P99), millis(l.P999), millis(l.Max),
+			itoa(i.Written),
+		})
+	}
+	mempool := [][]string{{"time", "elapsed_s", "source", "pending", "queued", "bytes"}}
+	for _, s := range r.Mempool {
+		mempool = append(mempool, []string{
+			s.Time.Format(time.RFC3339Nano), seconds(s.Time.Sub(r.StartedAt)), s.Source, itoa(s.Pending), itoa(s.Queued), itoa(s.Bytes),
+		})
+	}
+	return map[string][][]string{
+		"intervals":    intervals,
+		"mempool":      mempool,
+		"blocks":       blockRows(r.Blocks, r.StartedAt),
+		"block_series": blockRows(r.BlockSeries, r.StartedAt),
+	}
+}
+
+func blockRows(samples []types.BlockSample, start time.Time) [][]string {
+	rows := [][]string{{"number", "arrived_at", "elapsed_s", "interval_ms", "txs", "gas_used", "gas_limit"}}
+	for _, b := range samples {
+		rows = append(rows, []string{
+			strconv.FormatUint(b.Number, 10), b.ArrivedAt.Format(time.RFC3339Nano), seconds(b.ArrivedAt.Sub(start)),
+			millis(b.Interval), itoa(int64(b.Txs)),
+			strconv.FormatUint(b.GasUsed, 10), strconv.FormatUint(b.GasLimit, 10),
+		})
+	}
+	return rows
+}
+
+func writeCsv(path string, rows [][]string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(rows); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+func millis(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 3, 64)
+}
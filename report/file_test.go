@@ -0,0 +1,64 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+)
+
+func TestWriteRunFile(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	rep := &Report{
+		Scenario:  "eth_transfer_to_self",
+		StartedAt: start,
+		EndedAt:   start.Add(3 * time.Second),
+		Succeeded: 20,
+		Errors:    map[types.ErrorCategory]int64{types.CategoryUnderpriced: 2},
+		Intervals: []types.Interval{
+			{Start: start, Sent: 10, Latency: types.LatencySummary{Count: 10, P50: 2 * time.Millisecond}},
+			{Start: start.Add(time.Second), Sent: 10, Failed: 2},
+		},
+		BlockSeries: []types.BlockSample{
+			{Number: 2, ArrivedAt: start.Add(time.Second), Interval: time.Second, Txs: 10, GasUsed: 50, GasLimit: 100},
+		},
+	}
+	paths, err := WriteRunFile(dir, RunFile{
+		Version: "v1.0.0",
+		Command: "evmtx",
+		Config:  map[string]interface{}{"evmtx": map[string]interface{}{"tpu": 10}},
+		Report:  rep,
+	}, true)
+	require.NoError(t, err)
+	base := filepath.Join(dir, "evmtx-20240501-120000")
+	require.Equal(t, []string{base + ".json", base + "-intervals.csv", base + "-block_series.csv"}, paths)
+
+	b, err := os.ReadFile(base + ".json")
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	require.Equal(t, "v1.0.0", decoded["version"])
+	require.Equal(t, "eth_transfer_to_self", decoded["scenario"])
+	require.Equal(t, float64(10), decoded["config"].(map[string]interface{})["evmtx"].(map[string]interface{})["tpu"])
+	require.Len(t, decoded["intervals"], 2)
+
+	f, err := os.Open(base + "-intervals.csv")
+	require.NoError(t, err)
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+	require.Equal(t, []string{"2024-05-01T12:00:01Z", "1.000", "10", "2"}, rows[2][:4])
+	require.Equal(t, "2.000", rows[1][6])
+
+	blocks, err := os.ReadFile(base + "-block_series.csv")
+	require.NoError(t, err)
+	require.Contains(t, string(blocks), "2,2024-05-01T12:00:01Z,1.000,1000.000,10,50,100")
+}
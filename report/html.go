@@ -0,0 +1,262 @@
+package report
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"loadtester/types"
+)
+
+//go:embed templates/report.html.tmpl
+var templates embed.FS
+
+var htmlTemplate = template.Must(template.ParseFS(templates, "templates/report.html.tmpl"))
+
+type row struct {
+	Name  string
+	Value string
+}
+
+type latencyRow struct {
+	Name string
+	L    types.LatencySummary
+}
+
+type htmlPage struct {
+	Command, Scenario, Version    string
+	Started, LoadStopped, Ended   string
+	Summary                       []row
+	Health                        *types.BlockHealth
+	Latencies                     []latencyRow
+	Config                        []row
+	ThroughputChart, LatencyChart template.HTML
+	ErrorChart, GasChart          template.HTML
+	MempoolChart                  template.HTML
+}
+
+// RenderHTML writes the run as a single html page with inline svg charts, so
+// it can be read offline.
+func RenderHTML(w io.Writer, run RunFile) error {
+	r := run.Report
+	page := htmlPage{
+		Command:         run.Command,
+		Scenario:        r.Scenario,
+		Version:         run.Version,
+		Started:         formatTime(r.StartedAt),
+		LoadStopped:     formatTime(r.LoadStoppedAt),
+		Ended:           formatTime(r.EndedAt),
+		Summary:         r.summaryRows(),
+		Health:          r.BlockHealth,
+		Latencies:       r.latencyRows(),
+		Config:          flattenConfig("", run.Config),
+		ThroughputChart: r.throughputChart(),
+		LatencyChart:    r.latencyChart(),
+		ErrorChart:      r.errorChart(),
+		GasChart:        r.gasChart(),
+		MempoolChart:    r.mempoolChart(),
+	}
+	return htmlTemplate.Execute(w, page)
+}
+
+func (r *Report) summaryRows() []row {
+	rows := []row{
+		{"succeeded", fmt.Sprint(r.Succeeded)},
+		{"failed", fmt.Sprint(r.Failed)},
+	}
+	if f := r.FireAndForget; f != nil {
+		rows = append(rows, row{"written without response", fmt.Sprint(f.Written)})
+	}
+	rows = append(rows, []row{
+		{"time spent", r.TimeSpent.String()},
+		{"target tpu", fmt.Sprintf("%d per %s", r.TargetTpu, r.TimeUnit)},
+	}...)
+	if r.TimeSpent > 0 && r.TimeUnit > 0 {
+		rows = append(rows, row{"client tpu", fmt.Sprintf("%.2f", r.Tpu())})
+	}
+	if t := r.Throughput; t != nil && t.Blocks > 0 {
+		rows = append(rows,
+			row{"on-chain tps", fmt.Sprintf("%.2f", t.Tps)},
+			row{"blocks", fmt.Sprintf("%d (%d-%d)", t.Blocks, t.FromBlock, t.ToBlock)},
+			row{"txs per block", fmt.Sprintf("%.1f", t.TxsPerBlock)},
+			row{"gas per block", fmt.Sprintf("%.0f", t.GasPerBlock)},
+		)
+	}
+	if i := r.Inclusion; i != nil {
+		rows = append(rows, row{"included", fmt.Sprintf("%d of %d tracked, %d reverted, %d pending",
+			i.Included, i.Tracked, i.Reverted, i.Pending)})
+	}
+	return rows
+}
+
+func (r *Report) latencyRows() []latencyRow {
+	var rows []latencyRow
+	if r.Latency != nil && r.Latency.Total.Count > 0 {
+		rows = append(rows, latencyRow{"send (" + r.Latency.Scenario + ")", r.Latency.Total})
+		endpoints := make([]string, 0, len(r.Latency.Endpoints))
+		for endpoint := range r.Latency.Endpoints {
+			endpoints = append(endpoints, endpoint)
+		}
+		sort.Strings(endpoints)
+		for _, endpoint := range endpoints {
+			rows = append(rows, latencyRow{endpoint, r.Latency.Endpoints[endpoint]})
+		}
+	}
+	for _, stage := range types.Stages {
+		if l, ok := r.Stages[stage]; ok {
+			rows = append(rows, latencyRow{"stage " + string(stage), l})
+		}
+	}
+	return rows
+}
+
+// throughputChart draws the sent and failed txs per second of every round and
+// the included txs per second of block time.
+func (r *Report) throughputChart() template.HTML {
+	sent := series{Name: "sent/s"}
+	written := series{Name: "written/s"}
+	failed := series{Name: "failed/s"}
+	for i, interval := range r.Intervals {
+		d := r.TimeUnit
+		if i+1 < len(r.Intervals) {
+			d = r.Intervals[i+1].Start.Sub(interval.Start)
+		}
+		if d <= 0 {
+			continue
+		}
+		x := interval.Start.Sub(r.StartedAt).Seconds()
+		sent.Points = append(sent.Points, point{x, float64(interval.Sent) / d.Seconds()})
+		written.Points = append(written.Points, point{x, float64(interval.Written) / d.Seconds()})
+		failed.Points = append(failed.Points, point{x, float64(interval.Failed) / d.Seconds()})
+	}
+	included := series{Name: "included/s", Points: r.includedPerSecond()}
+	lines := []series{sent, failed, included}
+	if r.FireAndForget != nil {
+		lines = []series{written, failed, included}
+	}
+	return lineChart("throughput", "seconds since start", "txs per second", lines)
+}
+
+func (r *Report) latencyChart() template.HTML {
+	p50, p90, p99 := series{Name: "p50"}, series{Name: "p90"}, series{Name: "p99"}
+	for _, interval := range r.Intervals {
+		if interval.Latency.Count == 0 {
+			continue
+		}
+		x := interval.Start.Sub(r.StartedAt).Seconds()
+		p50.Points = append(p50.Points, point{x, millis64(interval.Latency.P50)})
+		p90.Points = append(p90.Points, point{x, millis64(interval.Latency.P90)})
+		p99.Points = append(p99.Points, point{x, millis64(interval.Latency.P99)})
+	}
+	return lineChart("send latency per round", "seconds since start", "ms", []series{p50, p90, p99})
+}
+
+func (r *Report) errorChart() template.HTML {
+	categories := make([]string, 0, len(r.Errors))
+	for category := range r.Errors {
+		categories = append(categories, string(category))
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		return r.Errors[types.ErrorCategory(categories[i])] > r.Errors[types.ErrorCategory(categories[j])]
+	})
+	counts := make([]float64, len(categories))
+	for i, category := range categories {
+		counts[i] = float64(r.Errors[types.ErrorCategory(category)])
+	}
+	return barChart("errors by category", categories, counts)
+}
+
+func (r *Report) gasChart() template.HTML {
+	ratio := series{Name: "gas used %"}
+	for _, b := range r.blocks() {
+		if b.GasLimit > 0 {
+			ratio.Points = append(ratio.Points, point{b.ArrivedAt.Sub(r.StartedAt).Seconds(), float64(b.GasUsed) * 100 / float64(b.GasLimit)})
+		}
+	}
+	return lineChart("block gas usage", "seconds since start", "% of the block gas limit", []series{ratio})
+}
+
+func (r *Report) mempoolChart() template.HTML {
+	pending, queued := series{Name: "pending"}, series{Name: "queued"}
+	for _, s := range r.Mempool {
+		x := s.Time.Sub(r.StartedAt).Seconds()
+		pending.Points = append(pending.Points, point{x, float64(s.Pending)})
+		queued.Points = append(queued.Points, point{x, float64(s.Queued)})
+	}
+	return lineChart("mempool size", "seconds since start", "txs", []series{pending, queued})
+}
+
+// includedPerSecond sums the txs of the blocks per second, since block
+// timestamps have a resolution of a second and several blocks may share one,
+// and spreads every sum over the seconds since the previous one.
+func (r *Report) includedPerSecond() []point {
+	var points []point
+	var txs int
+	var second, previous time.Time
+	flush := func() {
+		if d := second.Sub(previous); d > 0 {
+			points = append(points, point{second.Sub(r.StartedAt).Seconds(), float64(txs) / d.Seconds()})
+		}
+	}
+	for _, b := range r.blocks() {
+		at := b.ArrivedAt.Truncate(time.Second)
+		if second.IsZero() {
+			// the first second only counts when the block before is known
+			second, previous = at, b.ArrivedAt.Add(-b.Interval).Truncate(time.Second)
+		}
+		if at.After(second) {
+			flush()
+			second, previous, txs = at, second, 0
+		}
+		txs += b.Txs
+	}
+	if !second.IsZero() {
+		flush()
+	}
+	return points
+}
+
+// blocks returns the blocks read from the chain, or the ones received by the
+// head monitor for older run files.
+func (r *Report) blocks() []types.BlockSample {
+	if len(r.BlockSeries) > 0 {
+		return r.BlockSeries
+	}
+	return r.Blocks
+}
+
+// flattenConfig turns the nested config into sorted section.key rows.
+func flattenConfig(prefix string, config map[string]interface{}) []row {
+	var rows []row
+	for key, value := range config {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			rows = append(rows, flattenConfig(name, nested)...)
+			continue
+		}
+		if f, ok := value.(float64); ok {
+			value = strconv.FormatFloat(f, 'f', -1, 64) // numbers decoded from json
+		}
+		rows = append(rows, row{name, fmt.Sprint(value)})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+	return rows
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func millis64(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
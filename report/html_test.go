@@ -0,0 +1,67 @@
+package report
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"loadtester/types"
+)
+
+func TestRenderHTML(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	rep := &Report{
+		Scenario:  "eth_transfer_to_self",
+		StartedAt: start,
+		EndedAt:   start.Add(3 * time.Second),
+		TimeUnit:  time.Second,
+		Succeeded: 20,
+		Errors:    map[types.ErrorCategory]int64{types.CategoryUnderpriced: 2},
+		Intervals: []types.Interval{
+			{Start: start, Sent: 10, Latency: types.LatencySummary{Count: 10, P50: 2 * time.Millisecond}},
+			{Start: start.Add(time.Second), Sent: 10, Failed: 2},
+		},
+		BlockSeries: []types.BlockSample{
+			{Number: 2, ArrivedAt: start.Add(time.Second), Interval: time.Second, Txs: 10, GasUsed: 50, GasLimit: 100},
+		},
+	}
+	dir := t.TempDir()
+	paths, err := WriteRunFile(dir, RunFile{
+		Version: "v1.0.0",
+		Command: "evmtx",
+		Config:  map[string]interface{}{"evmtx": map[string]interface{}{"gas_price": 201417240}},
+		Report:  rep,
+	}, false)
+	require.NoError(t, err)
+	run, err := ReadRunFile(filepath.Join(dir, filepath.Base(paths[0])))
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, RenderHTML(&out, run))
+	html := out.String()
+	require.Contains(t, html, "<svg")
+	require.Contains(t, html, "evmtx.gas_price")
+	require.Contains(t, html, "201417240")
+	require.Contains(t, html, string(types.CategoryUnderpriced))
+	require.NotContains(t, html, "<script")
+}
+
+func TestIncludedPerSecond(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	rep := &Report{
+		StartedAt: start,
+		BlockSeries: []types.BlockSample{
+			{ArrivedAt: start.Add(time.Second), Interval: time.Second, Txs: 10},
+			{ArrivedAt: start.Add(time.Second), Txs: 6}, // same second
+			{ArrivedAt: start.Add(3 * time.Second), Interval: 2 * time.Second, Txs: 8},
+		},
+	}
+	require.Equal(t, []point{{1, 16}, {3, 4}}, rep.includedPerSecond())
+
+	// the first second is left out when the block before shares it
+	rep.BlockSeries[0].Interval = 0
+	require.Equal(t, []point{{3, 4}}, rep.includedPerSecond())
+}
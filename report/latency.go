@@ -0,0 +1,26 @@
+package report
+
+import (
+	"sort"
+
+	"github.com/rs/zerolog/log"
+)
+
+// LogLatency logs the send latency percentiles of the scenario and of every
+// endpoint.
+func (r *Report) LogLatency() {
+	l := r.Latency
+	if l == nil || l.Total.Count == 0 {
+		return
+	}
+	log.Info().Msgf("send latency over %d rounds:", len(r.Intervals))
+	logLatency(l.Scenario, l.Total)
+	endpoints := make([]string, 0, len(l.Endpoints))
+	for endpoint := range l.Endpoints {
+		endpoints = append(endpoints, endpoint)
+	}
+	sort.Strings(endpoints)
+	for _, endpoint := range endpoints {
+		logLatency(endpoint, l.Endpoints[endpoint])
+	}
+}
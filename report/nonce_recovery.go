@@ -0,0 +1,23 @@
+package report
+
+import "github.com/rs/zerolog/log"
+
+// NonceRecoveryStats counts how nonce errors were handled. Fields are updated
+// with sync/atomic while the run is in progress.
+type NonceRecoveryStats struct {
+	Resyncs        int64 `json:"resyncs"`
+	Queried        int64 `json:"queried"` // resyncs which needed a nonce query
+	Resubmitted    int64 `json:"resubmitted"`
+	ResubmitFailed int64 `json:"resubmit_failed"`
+}
+
+// LogNonceRecovery logs the nonce recovery counters, if any nonce error happened.
+func (r *Report) LogNonceRecovery() {
+	s := r.NonceRecovery
+	if s.Resyncs == 0 {
+		return
+	}
+	log.Info().Msgf(
+		"nonce recovery: resyncs:%d, queried:%d, resubmitted:%d, resubmitFailed:%d",
+		s.Resyncs, s.Queried, s.Resubmitted, s.ResubmitFailed)
+}
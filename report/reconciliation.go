@@ -0,0 +1,38 @@
+package report
+
+import (
+	"github.com/rs/zerolog/log"
+
+	"loadtester/types"
+)
+
+// maxLogged caps the txs or accounts listed in the log, the report keeps all
+// of them.
+const maxLogged = 20
+
+// LogReconciliation logs the state of the accepted txs and the senders and
+// nonces of the pending and dropped ones.
+func (r *Report) LogReconciliation() {
+	rec := r.Reconciliation
+	if rec == nil {
+		return
+	}
+	log.Info().Msgf("reconciliation: included:%d, includedReverted:%d, pending:%d, dropped:%d, replaced:%d, unknown:%d",
+		rec.Counts[types.TxIncluded], rec.Counts[types.TxIncludedReverted], rec.Counts[types.TxPending],
+		rec.Counts[types.TxDropped], rec.Counts[types.TxReplaced], rec.Counts[types.TxUnknown])
+	logSentTxs("pending", rec.Pending)
+	if len(rec.Dropped) > 0 {
+		log.Warn().Msgf("%d accepted txs were dropped by the node", len(rec.Dropped))
+	}
+	logSentTxs("dropped", rec.Dropped)
+}
+
+func logSentTxs(state string, txs []types.SentTx) {
+	for i, tx := range txs {
+		if i == maxLogged {
+			log.Info().Msgf("  ... %d more %s txs", len(txs)-maxLogged, state)
+			return
+		}
+		log.Info().Msgf("  %s: sender:%s, nonce:%d, hash:%s", state, tx.From.Hex(), tx.Nonce, tx.Hash.Hex())
+	}
+}
@@ -0,0 +1,239 @@
+package report
+
+import (
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"loadtester/types"
+)
+
+// Report holds the results of a single evmtx run.
+type Report struct {
+	Scenario  string    `json:"scenario"`
+	StartedAt time.Time `json:"started_at"`
+	// LoadStoppedAt is when sending stopped, before waiting for pending txs
+	LoadStoppedAt time.Time                     `json:"load_stopped_at"`
+	EndedAt       time.Time                     `json:"ended_at"`
+	TimeUnit      time.Duration                 `json:"time_unit"`
+	TargetTpu     int                           `json:"target_tpu"`
+	TimeSpent     time.Duration                 `json:"time_spent"`
+	Succeeded     int                           `json:"succeeded"`
+	Failed        int64                         `json:"failed"`
+	Errors        map[types.ErrorCategory]int64 `json:"errors"`
+	Failovers     []types.FailoverEvent         `json:"failovers,omitempty"`
+
+	NonceRecovery NonceRecoveryStats `json:"nonce_recovery"`
+	Retries       types.RetryStats   `json:"retries"`
+	// FireAndForget is only set when txs were sent without waiting for responses
+	FireAndForget *types.FireAndForgetStats `json:"fire_and_forget,omitempty"`
+	// Intervals is the time series of the rounds of the run
+	Intervals []types.Interval `json:"intervals,omitempty"`
+	// Latency holds the send latency of the scenario, per round and per endpoint
+	Latency *types.RequestLatency `json:"latency,omitempty"`
+	// Stages holds the latency percentiles of each stage of the tx lifecycle
+	Stages map[types.Stage]types.LatencySummary `json:"stages,omitempty"`
+	// Inclusion is only set when receipts were tracked
+	Inclusion *types.InclusionStats `json:"inclusion,omitempty"`
+	// Throughput is the on-chain throughput while the run was going on
+	Throughput *types.ThroughputStats `json:"throughput,omitempty"`
+	// BlockResults is only set when cometbft block_results were fetched
+	BlockResults *types.BlockResultStats `json:"block_results,omitempty"`
+	// BlockHealth analyses the blocks produced during and after the run
+	BlockHealth *types.BlockHealth `json:"block_health,omitempty"`
+	// BlockSeries is the time series of the blocks of the run as read from the
+	// chain, ArrivedAt being the block timestamp
+	BlockSeries []types.BlockSample `json:"block_series,omitempty"`
+	// Blocks is the time series of the head monitor, only set when subscribing to new blocks
+	Blocks []types.BlockSample `json:"blocks,omitempty"`
+	// FeeOrdering is only set when the gas price of the txs was spread over tiers
+	FeeOrdering *types.FeeOrdering `json:"fee_ordering,omitempty"`
+	// Accounts is only set when the senders were verified after the run
+	Accounts *types.AccountVerification `json:"accounts,omitempty"`
+	// Mempool is the mempool occupancy time series, sampled until receipts stop being tracked
+	Mempool []types.MempoolSample `json:"mempool,omitempty"`
+	// Reconciliation is only set when accepted txs were reconciled after the run
+	Reconciliation *types.Reconciliation `json:"reconciliation,omitempty"`
+}
+
+// Tpu returns the achieved client-side transactions per time unit, i.e. the
+// rate txs were accepted by the node, not the rate they made it on chain.
+func (r *Report) Tpu() float64 {
+	return r.perTimeUnit(float64(r.Succeeded))
+}
+
+func (r *Report) perTimeUnit(totalSent float64) float64 {
+	switch r.TimeUnit {
+	case time.Millisecond:
+		return totalSent / float64(r.TimeSpent.Milliseconds())
+	case time.Second:
+		return totalSent / r.TimeSpent.Seconds()
+	default:
+		return totalSent / (float64(r.TimeSpent) / float64(r.TimeUnit))
+	}
+}
+
+// LogErrors logs the number of failed submissions per error category.
+func (r *Report) LogErrors() {
+	logCategoryCounts(r.Errors)
+}
+
+func logCategoryCounts(counts map[types.ErrorCategory]int64) {
+	categories := make([]string, 0, len(counts))
+	for category := range counts {
+		categories = append(categories, string(category))
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		log.Info().Msgf("  %s: %d", category, counts[types.ErrorCategory(category)])
+	}
+}
+
+// LogRetries logs retries apart from first attempt failures, if any send failed.
+func (r *Report) LogRetries() {
+	s := r.Retries
+	if s.FirstAttemptFailures == 0 {
+		return
+	}
+	log.Info().Msgf(
+		"retries: firstAttemptFailures:%d, retriedTxs:%d, retryAttempts:%d, recovered:%d, alreadyKnownOnRetry:%d, exhausted:%d",
+		s.FirstAttemptFailures, s.RetriedTxs, s.RetryAttempts, s.Recovered, s.AlreadyKnownOnRetry, s.Exhausted)
+}
+
+// LogFireAndForget logs the offered load of a fire-and-forget run.
+func (r *Report) LogFireAndForget() {
+	s := r.FireAndForget
+	if s == nil {
+		return
+	}
+	log.Info().Msgf(
+		"fire-and-forget: written:%d, writeErrors:%d, offeredTpu:%.2f, responses:%d, non200:%d, unanswered:%d",
+		s.Written, s.WriteErrors, r.perTimeUnit(float64(s.Written)), s.Responses, s.Non200, s.Unanswered)
+	if s.Sampled > 0 {
+		log.Info().Msgf("  sampled:%d, sampledFailed:%d (%.2f%%)",
+			s.Sampled, s.SampledFailed, 100*float64(s.SampledFailed)/float64(s.Sampled))
+		logCategoryCounts(s.SampledErrors)
+	}
+}
+
+// LogFailovers logs every endpoint health change of the run.
+func (r *Report) LogFailovers() {
+	if len(r.Failovers) == 0 {
+		return
+	}
+	log.Info().Msgf("%d failover events during the run", len(r.Failovers))
+	for _, e := range r.Failovers {
+		state := "down"
+		if e.Healthy {
+			state = "up"
+		}
+		log.Info().Msgf("  %s %s %s: %s", e.Time.Format("15:04:05.000"), e.Endpoint, state, e.Reason)
+	}
+}
+
+// LogInclusion logs how many sent txs were included, their latency is logged
+// along with the other stages.
+func (r *Report) LogInclusion() {
+	s := r.Inclusion
+	if s == nil {
+		return
+	}
+	log.Info().Msgf("inclusion: tracked:%d, included:%d, reverted:%d, pending:%d, gasUsed:%d",
+		s.Tracked, s.Included, s.Reverted, s.Pending, s.GasUsed)
+}
+
+func logLatency(name string, l types.LatencySummary) {
+	if l.Count == 0 {
+		return
+	}
+	log.Info().Msgf("  %s: count:%d, min:%v, mean:%v, p50:%v, p90:%v, p99:%v, p99.9:%v, max:%v",
+		name, l.Count, l.Min, l.Mean, l.P50, l.P90, l.P99, l.P999, l.Max)
+}
+
+// LogThroughput logs the on-chain throughput.
+func (r *Report) LogThroughput() {
+	s := r.Throughput
+	if s == nil || s.Blocks == 0 {
+		return
+	}
+	log.Info().Msgf(
+		"on-chain: blocks:%d-%d, txs:%d, ourTxs:%d (%.2f%%), duration:%v, tps:%.2f, txsPerBlock:%.2f, gasPerBlock:%.0f",
+		s.FromBlock+1, s.ToBlock, s.Txs, s.OurTxs, 100*s.OurFraction, s.Duration, s.Tps, s.TxsPerBlock, s.GasPerBlock)
+}
+
+// LogBlocks summarizes the blocks received by the head monitor.
+func (r *Report) LogBlocks() {
+	if len(r.Blocks) == 0 {
+		return
+	}
+	var txs int
+	var gasUsed uint64
+	intervals := make([]time.Duration, 0, len(r.Blocks))
+	for i, b := range r.Blocks {
+		txs += b.Txs
+		gasUsed += b.GasUsed
+		if i > 0 {
+			intervals = append(intervals, b.Interval)
+		}
+	}
+	log.Info().Msgf("received blocks: %d, txs:%d, gasUsed:%d", len(r.Blocks), txs, gasUsed)
+	logLatency("block arrival interval", types.SummarizeLatencies(intervals))
+}
+
+// LogMempool summarizes the mempool occupancy time series.
+func (r *Report) LogMempool() {
+	if len(r.Mempool) == 0 {
+		return
+	}
+	var peak types.MempoolSample
+	for _, s := range r.Mempool {
+		if s.Pending+s.Queued > peak.Pending+peak.Queued {
+			peak = s
+		}
+	}
+	first, last := r.Mempool[0], r.Mempool[len(r.Mempool)-1]
+	log.Info().Msgf("mempool (%s, %d samples): first:%d, peak:%d at %s, last:%d, peakBytes:%d",
+		last.Source, len(r.Mempool), first.Pending+first.Queued, peak.Pending+peak.Queued,
+		peak.Time.Format("15:04:05"), last.Pending+last.Queued, peak.Bytes)
+}
+
+// LogBlockHealth logs whether the chain stayed healthy under load.
+func (r *Report) LogBlockHealth() {
+	h := r.BlockHealth
+	if h == nil || h.Blocks == 0 {
+		return
+	}
+	log.Info().Msgf(
+		"block production: blockTime mean:%v, jitter:%v, max:%v, emptyBlocks:%d, gasUsedRatio mean:%.2f%%, max:%.2f%%",
+		h.BlockTimeMean, h.BlockTimeJitter, h.BlockTimeMax, h.EmptyBlocks, 100*h.GasUsedRatioMean, 100*h.GasUsedRatioMax)
+	log.Info().Msgf("  theoreticalMaxTps:%.2f, achievedTps:%.2f, utilization:%.2f%%",
+		h.TheoreticalMaxTps, h.AchievedTps, 100*h.Utilization)
+	if h.CaughtUp {
+		log.Info().Msgf("  caught up %v after the load stopped", h.CatchUp)
+	}
+	if h.Healthy {
+		log.Info().Msg("  the chain stayed healthy under load")
+		return
+	}
+	for _, issue := range h.Issues {
+		log.Warn().Msgf("  unhealthy: %s", issue)
+	}
+}
+
+// LogBlockResults logs the tx results of the blocks and the failed txs per code.
+func (r *Report) LogBlockResults() {
+	s := r.BlockResults
+	if s == nil {
+		return
+	}
+	log.Info().Msgf("block results: txs:%d, failed:%d, gasWanted:%d, gasUsed:%d", s.Txs, s.Failed, s.GasWanted, s.GasUsed)
+	codes := make([]string, 0, len(s.Codes))
+	for code := range s.Codes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		log.Info().Msgf("  %s: %d", code, s.Codes[code])
+	}
+}
@@ -0,0 +1,57 @@
+package report
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"loadtester/types"
+)
+
+// StageTimings collects the duration of every tx per lifecycle stage in
+// histograms. It is safe for concurrent use.
+type StageTimings struct {
+	mu         sync.Mutex
+	histograms map[types.Stage]*types.Histogram
+}
+
+func NewStageTimings() *StageTimings {
+	return &StageTimings{histograms: make(map[types.Stage]*types.Histogram)}
+}
+
+// Add records the duration of one tx in the stage.
+func (s *StageTimings) Add(stage types.Stage, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	h, ok := s.histograms[stage]
+	if !ok {
+		h = types.NewHistogram()
+		s.histograms[stage] = h
+	}
+	h.Record(d)
+}
+
+// Summaries returns the percentiles of every stage with at least one duration.
+func (s *StageTimings) Summaries() map[types.Stage]types.LatencySummary {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	summaries := make(map[types.Stage]types.LatencySummary, len(s.histograms))
+	for stage, h := range s.histograms {
+		summaries[stage] = h.Summary()
+	}
+	return summaries
+}
+
+// LogStages logs the latency percentiles of each stage in lifecycle order.
+func (r *Report) LogStages() {
+	if len(r.Stages) == 0 {
+		return
+	}
+	log.Info().Msg("per-stage latencies:")
+	for _, stage := range types.Stages {
+		if l, ok := r.Stages[stage]; ok {
+			logLatency(string(stage), l)
+		}
+	}
+}
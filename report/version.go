@@ -0,0 +1,35 @@
+package report
+
+import "runtime/debug"
+
+// Version is the version of the tool written into run files, set at build
+// time with -ldflags "-X loadtester/report.Version=v1.2.3".
+var Version = ""
+
+// ToolVersion returns Version, or the vcs revision embedded by go build if it
+// isn't set.
+func ToolVersion() string {
+	if Version != "" {
+		return Version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+	var revision, modified string
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			revision = s.Value
+		case "vcs.modified":
+			modified = s.Value
+		}
+	}
+	if revision == "" {
+		return "dev"
+	}
+	if modified == "true" {
+		revision += "-dirty"
+	}
+	return revision
+}
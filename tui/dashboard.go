@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+const refreshInterval = 250 * time.Millisecond
+
+// Snapshot is the state of a run shown by the dashboard.
+type Snapshot struct {
+	Title       string
+	Paused      bool
+	TargetRate  float64 // txs per second
+	CurrentRate float64 // txs per second accepted or written in the last round
+	Succeeded   int64
+	Written     int64 // fire-and-forget txs, the node's response isn't awaited
+	Failed      int64
+	Errors      map[string]int64
+	// Latencies is the send latency of the recent rounds, oldest first
+	Latencies  []time.Duration
+	HasMempool bool
+	Mempool    int64
+	Block      uint64
+	Remaining  time.Duration
+	Logs       []string
+}
+
+// Controls are the run controls bound to the keyboard.
+type Controls interface {
+	TogglePause()
+	// ScaleRate multiplies the target rate by factor
+	ScaleRate(factor float64)
+	Stop()
+}
+
+// Start puts the terminal of in into raw mode and redraws the snapshot on the
+// alternate screen of out until the returned stop is called. Keys are read
+// from in by a goroutine which stays blocked in the read after stop, the next
+// key typed is swallowed by it and in shouldn't be read again.
+func Start(in *os.File, out io.Writer, snapshot func() Snapshot, controls Controls) (stop func(), err error) {
+	fd := int(in.Fd())
+	if !term.IsTerminal(fd) {
+		return nil, errors.New("stdin isn't a terminal")
+	}
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l") // alternate screen, hidden cursor
+
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(refreshInterval)
+		defer ticker.Stop()
+		for {
+			// raw mode doesn't return the carriage on line feeds
+			fmt.Fprint(out, "\x1b[H\x1b[2J"+strings.ReplaceAll(Render(snapshot()), "\n", "\r\n"))
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	// a read on a terminal can't be cancelled, so the reader outlives the
+	// dashboard until the next key and then drops it
+	go readKeys(in, controls, done)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+			fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+			_ = term.Restore(fd, oldState)
+		})
+	}, nil
+}
+
+func readKeys(in io.Reader, controls Controls, done <-chan struct{}) {
+	buf := make([]byte, 16)
+	for {
+		n, err := in.Read(buf)
+		if err != nil {
+			return
+		}
+		select {
+		case <-done:
+			return
+		default:
+		}
+		for _, key := range buf[:n] {
+			handleKey(key, controls)
+		}
+	}
+}
+
+func handleKey(key byte, controls Controls) {
+	switch key {
+	case 'p', ' ':
+		controls.TogglePause()
+	case '+', '=':
+		controls.ScaleRate(1.1)
+	case '-', '_':
+		controls.ScaleRate(0.9)
+	case 'q', 3: // ctrl-c doesn't raise a signal in raw mode
+		controls.Stop()
+	}
+}
+
+// Render draws the snapshot as plain text lines.
+func Render(s Snapshot) string {
+	b := &strings.Builder{}
+	state := "RUNNING"
+	if s.Paused {
+		state = "PAUSED"
+	}
+	fmt.Fprintf(b, "%s  [%s]\n", s.Title, state)
+	fmt.Fprintln(b, strings.Repeat("─", 60))
+	fmt.Fprintf(b, "rate       current %.1f tps, target %.1f tps\n", s.CurrentRate, s.TargetRate)
+	if s.Written > 0 {
+		fmt.Fprintf(b, "txs        succeeded %d, written %d, failed %d\n", s.Succeeded, s.Written, s.Failed)
+	} else {
+		fmt.Fprintf(b, "txs        succeeded %d, failed %d\n", s.Succeeded, s.Failed)
+	}
+	if len(s.Errors) > 0 {
+		categories := make([]string, 0, len(s.Errors))
+		for category := range s.Errors {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+		for i, category := range categories {
+			label := ""
+			if i == 0 {
+				label = "errors"
+			}
+			fmt.Fprintf(b, "%-10s %s %d\n", label, category, s.Errors[category])
+		}
+	}
+	if n := len(s.Latencies); n > 0 {
+		fmt.Fprintf(b, "latency    %s p99 %v\n", Sparkline(s.Latencies), s.Latencies[n-1].Round(time.Microsecond))
+	}
+	if s.HasMempool {
+		fmt.Fprintf(b, "mempool    %d txs\n", s.Mempool)
+	}
+	fmt.Fprintf(b, "block      #%d\n", s.Block)
+	fmt.Fprintf(b, "remaining  %v\n", s.Remaining.Round(time.Second))
+	fmt.Fprintln(b, strings.Repeat("─", 60))
+	for _, line := range s.Logs {
+		fmt.Fprintln(b, line)
+	}
+	fmt.Fprint(b, "[p] pause/resume  [+/-] rate ±10%  [q] stop")
+	return b.String()
+}
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws the values scaled between their min and max.
+func Sparkline(values []time.Duration) string {
+	if len(values) == 0 {
+		return ""
+	}
+	min, max := values[0], values[0]
+	for _, v := range values {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	line := make([]rune, len(values))
+	for i, v := range values {
+		level := 0
+		if max > min {
+			level = int(int64(v-min) * int64(len(sparks)-1) / int64(max-min))
+		}
+		line[i] = sparks[level]
+	}
+	return string(line)
+}
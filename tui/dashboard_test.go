@@ -0,0 +1,67 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeControls struct {
+	pauses, stops int
+	factors       []float64
+}
+
+func (f *fakeControls) TogglePause()             { f.pauses++ }
+func (f *fakeControls) ScaleRate(factor float64) { f.factors = append(f.factors, factor) }
+func (f *fakeControls) Stop()                    { f.stops++ }
+
+func TestHandleKey(t *testing.T) {
+	controls := &fakeControls{}
+	for _, key := range []byte("p+-x q\x03") {
+		handleKey(key, controls)
+	}
+	require.Equal(t, 2, controls.pauses)
+	require.Equal(t, []float64{1.1, 0.9}, controls.factors)
+	require.Equal(t, 2, controls.stops)
+}
+
+func TestSparkline(t *testing.T) {
+	ms := time.Millisecond
+	require.Equal(t, "▁▄█▁", Sparkline([]time.Duration{10 * ms, 15 * ms, 20 * ms, 10 * ms}))
+	require.Equal(t, "▁▁", Sparkline([]time.Duration{ms, ms}))
+	require.Empty(t, Sparkline(nil))
+}
+
+func TestRender(t *testing.T) {
+	out := Render(Snapshot{
+		Title:       "loadtester evmtx: eth_transfer_to_self",
+		Paused:      true,
+		TargetRate:  1000,
+		CurrentRate: 987.5,
+		Succeeded:   1200,
+		Failed:      3,
+		Errors:      map[string]int64{"underpriced": 2, "invalid_nonce": 1},
+		Latencies:   []time.Duration{time.Millisecond, 2 * time.Millisecond},
+		HasMempool:  true,
+		Mempool:     42,
+		Block:       1234,
+		Remaining:   90 * time.Second,
+		Logs:        []string{"12:00:00 WRN something"},
+	})
+	for _, expected := range []string{
+		"[PAUSED]", "current 987.5 tps, target 1000.0 tps", "succeeded 1200, failed 3",
+		"errors     invalid_nonce 1", "underpriced 2", "▁█ p99 2ms", "mempool    42 txs",
+		"block      #1234", "remaining  1m30s", "WRN something",
+	} {
+		require.True(t, strings.Contains(out, expected), "missing %q in\n%s", expected, out)
+	}
+}
+
+func TestLogBuffer(t *testing.T) {
+	lb := NewLogBuffer(2)
+	_, _ = lb.Write([]byte("a\n"))
+	_, _ = lb.Write([]byte("b\nc\n"))
+	require.Equal(t, []string{"b", "c"}, lb.Lines())
+}
@@ -0,0 +1,36 @@
+package tui
+
+import (
+	"strings"
+	"sync"
+)
+
+// LogBuffer keeps the last lines written to it, so logs can be shown inside
+// the dashboard instead of scrolling it away.
+type LogBuffer struct {
+	mu    sync.Mutex
+	lines []string
+	max   int
+}
+
+func NewLogBuffer(max int) *LogBuffer {
+	return &LogBuffer{max: max}
+}
+
+func (lb *LogBuffer) Write(p []byte) (int, error) {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		lb.lines = append(lb.lines, line)
+	}
+	if len(lb.lines) > lb.max {
+		lb.lines = append([]string(nil), lb.lines[len(lb.lines)-lb.max:]...)
+	}
+	return len(p), nil
+}
+
+func (lb *LogBuffer) Lines() []string {
+	lb.mu.Lock()
+	defer lb.mu.Unlock()
+	return append([]string(nil), lb.lines...)
+}
@@ -0,0 +1,54 @@
+package tui
+
+import (
+	"io"
+	"os"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// Logs is the output of the global logger, set up by the root command. The
+// dashboard captures it instead of replacing the global logger, which other
+// goroutines keep using while it runs.
+var Logs = NewLogSwitch(os.Stderr)
+
+// LogSwitch writes the logs to the console until they are captured.
+type LogSwitch struct {
+	mu      sync.Mutex
+	console io.Writer
+	capture io.Writer
+	level   zerolog.Level
+}
+
+func NewLogSwitch(console io.Writer) *LogSwitch {
+	return &LogSwitch{console: console}
+}
+
+func (s *LogSwitch) Write(p []byte) (int, error) {
+	return s.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (s *LogSwitch) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.capture == nil {
+		return s.console.Write(p)
+	}
+	if level < s.level {
+		return len(p), nil
+	}
+	return s.capture.Write(p)
+}
+
+// Capture sends the logs of at least level to w until release is called.
+func (s *LogSwitch) Capture(w io.Writer, level zerolog.Level) (release func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.capture, s.level = w, level
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.capture = nil
+	}
+}
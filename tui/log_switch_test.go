@@ -0,0 +1,29 @@
+package tui
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogSwitch(t *testing.T) {
+	console, captured := &bytes.Buffer{}, &bytes.Buffer{}
+	logs := NewLogSwitch(console)
+	logger := zerolog.New(logs)
+
+	logger.Info().Msg("before")
+	release := logs.Capture(captured, zerolog.WarnLevel)
+	logger.Info().Msg("dropped")
+	logger.Warn().Msg("captured")
+	release()
+	logger.Info().Msg("after")
+
+	require.Contains(t, console.String(), "before")
+	require.Contains(t, console.String(), "after")
+	require.NotContains(t, console.String(), "captured")
+	require.Contains(t, captured.String(), "captured")
+	require.NotContains(t, captured.String(), "dropped")
+	require.NotContains(t, captured.String(), "after")
+}
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AccountMismatch is a sender whose on-chain state doesn't match what the run
+// expects from the txs it sent.
+type AccountMismatch struct {
+	Address      common.Address `json:"address"`
+	LocalNonce   uint64         `json:"local_nonce"`
+	OnchainNonce uint64         `json:"onchain_nonce"`
+	// spend is the balance difference since the start of the run, expected
+	// within [ExpectedMin, ExpectedMax] depending on the gas actually used
+	Spend       *big.Int `json:"spend"`
+	ExpectedMin *big.Int `json:"expected_min"`
+	ExpectedMax *big.Int `json:"expected_max"`
+	NonceDrift  bool     `json:"nonce_drift"`
+	BalanceOff  bool     `json:"balance_off"`
+}
+
+// AccountVerification compares the on-chain nonce and balance of every sender
+// with the local state after a run.
+type AccountVerification struct {
+	Checked     int               `json:"checked"`
+	Failed      int               `json:"failed"` // senders whose state couldn't be queried
+	NonceDrifts int               `json:"nonce_drifts"`
+	BalanceOffs int               `json:"balance_offs"`
+	Mismatches  []AccountMismatch `json:"mismatches,omitempty"`
+}
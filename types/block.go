@@ -0,0 +1,27 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Block is the part of an eth_getBlockByNumber result, without full txs, the
+// load tester looks at.
+type Block struct {
+	Number       hexutil.Uint64 `json:"number"`
+	Hash         common.Hash    `json:"hash"`
+	Timestamp    hexutil.Uint64 `json:"timestamp"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	GasLimit     hexutil.Uint64 `json:"gasLimit"`
+	Transactions []common.Hash  `json:"transactions"`
+}
+
+// Receipt is the part of an eth_getTransactionReceipt result the load tester
+// looks at.
+type Receipt struct {
+	TxHash      common.Hash    `json:"transactionHash"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	TxIndex     hexutil.Uint64 `json:"transactionIndex"`
+	Status      hexutil.Uint64 `json:"status"`
+	GasUsed     hexutil.Uint64 `json:"gasUsed"`
+}
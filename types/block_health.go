@@ -0,0 +1,27 @@
+package types
+
+import "time"
+
+// BlockHealth describes how the chain produced blocks during and after a run.
+type BlockHealth struct {
+	Blocks           int           `json:"blocks"`
+	BlockTimeMean    time.Duration `json:"block_time_mean"`
+	BlockTimeJitter  time.Duration `json:"block_time_jitter"` // standard deviation
+	BlockTimeMax     time.Duration `json:"block_time_max"`
+	EmptyBlocks      int           `json:"empty_blocks"`
+	EmptyUnderLoad   int           `json:"empty_under_load"` // empty blocks between the first txs and the load stop
+	GasLimit         uint64        `json:"gas_limit"`
+	GasUsedRatioMean float64       `json:"gas_used_ratio_mean"`
+	GasUsedRatioMax  float64       `json:"gas_used_ratio_max"`
+	// CatchUp is how long after the load stopped the first block following the
+	// last one holding txs of the run came, only set if CaughtUp
+	CaughtUp bool          `json:"caught_up"`
+	CatchUp  time.Duration `json:"catch_up"`
+	// TheoreticalMaxTps is what fits in the block gas limit at the mean block
+	// time, given the mean gas per tx
+	TheoreticalMaxTps float64  `json:"theoretical_max_tps"`
+	AchievedTps       float64  `json:"achieved_tps"`
+	Utilization       float64  `json:"utilization"` // AchievedTps / TheoreticalMaxTps
+	Healthy           bool     `json:"healthy"`
+	Issues            []string `json:"issues,omitempty"`
+}
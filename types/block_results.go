@@ -0,0 +1,11 @@
+package types
+
+// BlockResultStats aggregates the cometbft block_results of a block range.
+type BlockResultStats struct {
+	Txs       int64 `json:"txs"`
+	Failed    int64 `json:"failed"`
+	GasWanted int64 `json:"gas_wanted"`
+	GasUsed   int64 `json:"gas_used"`
+	// Codes counts the failed txs per "codespace:code"
+	Codes map[string]int64 `json:"codes,omitempty"`
+}
@@ -0,0 +1,13 @@
+package types
+
+import "time"
+
+// BlockSample is a block as seen by the head monitor when it arrived.
+type BlockSample struct {
+	Number    uint64        `json:"number"`
+	ArrivedAt time.Time     `json:"arrived_at"`
+	Interval  time.Duration `json:"interval"` // since the arrival of the previous block
+	Txs       int           `json:"txs"`
+	GasUsed   uint64        `json:"gas_used"`
+	GasLimit  uint64        `json:"gas_limit"`
+}
@@ -5,8 +5,11 @@ import (
 )
 
 type NonceError struct {
+	Code    int
 	Message string
-	Nonce   uint64
+	Nonce   uint64 // nonce expected by the node
+	// HasNonce is false when the message doesn't tell the expected nonce.
+	HasNonce bool
 }
 
 func (e *NonceError) Error() string {
@@ -16,4 +19,7 @@ func (e *NonceError) Error() string {
 var (
 	ErrorInsufficientFund   = errors.New("insufficient fund")
 	ErrorFailedToFetchNonce = errors.New("failed to fetch nonce")
+	ErrorNoHealthyEndpoint  = errors.New("no healthy endpoint")
+	ErrorRpcCallFailed      = errors.New("json-rpc call failed")
+	ErrorConnectionClosed   = errors.New("connection closed")
 )
@@ -0,0 +1,11 @@
+package types
+
+import "time"
+
+// FailoverEvent records an endpoint changing its health state.
+type FailoverEvent struct {
+	Time     time.Time `json:"time"`
+	Endpoint string    `json:"endpoint"`
+	Healthy  bool      `json:"healthy"`
+	Reason   string    `json:"reason"`
+}
@@ -0,0 +1,24 @@
+package types
+
+// FeeOrdering measures whether the txs of a run were included in the order of
+// their gas price, which is the priority of legacy txs in the mempool.
+// Positions are ordered by block number, then by index in the block. Only
+// pairs of txs of different senders which were in the mempool together are
+// compared.
+type FeeOrdering struct {
+	Txs    int64 `json:"txs"`    // included txs of the run
+	Blocks int   `json:"blocks"` // blocks holding at least two of them
+	// Correlation is the kendall rank correlation between the gas price and
+	// the inclusion position over the compared pairs, -1 when higher fees are
+	// always included first
+	Correlation float64 `json:"correlation"`
+	// BlockCorrelation is the same over the pairs in the same block
+	BlockCorrelation float64 `json:"block_correlation"`
+	// Inversions counts the pairs where a lower-fee tx was included ahead of a
+	// higher-fee one, out of Pairs compared pairs with different fees
+	Inversions int64 `json:"inversions"`
+	Pairs      int64 `json:"pairs"`
+	// BlockInversions and BlockPairs only count pairs in the same block
+	BlockInversions int64 `json:"block_inversions"`
+	BlockPairs      int64 `json:"block_pairs"`
+}
@@ -0,0 +1,14 @@
+package types
+
+// FireAndForgetStats describes the client-side offered load of fire-and-forget
+// sends along with the outcome of the sampled responses.
+type FireAndForgetStats struct {
+	Written       int64                   `json:"written"`
+	WriteErrors   int64                   `json:"write_errors"`
+	Responses     int64                   `json:"responses"`  // responses drained, parsed or not
+	Non200        int64                   `json:"non_200"`    // drained responses with a non 200 status
+	Unanswered    int64                   `json:"unanswered"` // requests lost with their connection
+	Sampled       int64                   `json:"sampled"`
+	SampledFailed int64                   `json:"sampled_failed"`
+	SampledErrors map[ErrorCategory]int64 `json:"sampled_errors,omitempty"`
+}
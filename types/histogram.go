@@ -0,0 +1,146 @@
+package types
+
+import (
+	"math"
+	"math/bits"
+	"time"
+)
+
+const (
+	// histograms record microseconds with 3 significant digits, up to an hour
+	histogramUnit      = time.Microsecond
+	histogramHighest   = int64(time.Hour / histogramUnit)
+	subBucketHalfMag   = 10 // 2 * 10^3 values need 11 bits per bucket
+	subBucketHalfCount = 1 << subBucketHalfMag
+	subBucketCount     = 2 * subBucketHalfCount
+	subBucketMask      = subBucketCount - 1
+	// histogramBuckets is the smallest count with subBucketCount << (buckets-1) > histogramHighest
+	histogramBuckets   = 22
+	histogramCountsLen = (histogramBuckets + 1) * subBucketHalfCount
+)
+
+// Histogram is a high dynamic range histogram of latencies. Every bucket is
+// half as precise as the next one, keeping a relative error below 0.1% from a
+// microsecond to an hour in constant memory. It isn't safe for concurrent use.
+type Histogram struct {
+	counts [histogramCountsLen]int64
+	total  int64
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+func NewHistogram() *Histogram {
+	return &Histogram{}
+}
+
+// Record adds a latency, values above an hour are counted as an hour but
+// still reported as the max.
+func (h *Histogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	v := int64(d / histogramUnit)
+	if v > histogramHighest {
+		v = histogramHighest
+	}
+	h.counts[countsIndex(v)]++
+	if h.total == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.total++
+	h.sum += d
+}
+
+// Merge adds every latency recorded by o.
+func (h *Histogram) Merge(o *Histogram) {
+	if o.total == 0 {
+		return
+	}
+	for i, c := range o.counts {
+		h.counts[i] += c
+	}
+	if h.total == 0 || o.min < h.min {
+		h.min = o.min
+	}
+	if o.max > h.max {
+		h.max = o.max
+	}
+	h.total += o.total
+	h.sum += o.sum
+}
+
+// Reset drops every recorded latency.
+func (h *Histogram) Reset() {
+	*h = Histogram{}
+}
+
+func (h *Histogram) Count() int64 {
+	return h.total
+}
+
+// Percentile returns the latency below which the fraction p of the latencies
+// fall, given as the highest value of its bucket.
+func (h *Histogram) Percentile(p float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	target := int64(math.Ceil(p * float64(h.total)))
+	if target < 1 {
+		target = 1
+	}
+	var seen int64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= target {
+			d := time.Duration(highestEquivalentValue(i)) * histogramUnit
+			if d > h.max {
+				return h.max
+			}
+			if d < h.min {
+				return h.min
+			}
+			return d
+		}
+	}
+	return h.max
+}
+
+// Summary returns the percentiles of the recorded latencies.
+func (h *Histogram) Summary() LatencySummary {
+	if h.total == 0 {
+		return LatencySummary{}
+	}
+	return LatencySummary{
+		Count: int(h.total),
+		Min:   h.min,
+		Mean:  h.sum / time.Duration(h.total),
+		P50:   h.Percentile(0.50),
+		P90:   h.Percentile(0.90),
+		P99:   h.Percentile(0.99),
+		P999:  h.Percentile(0.999),
+		Max:   h.max,
+	}
+}
+
+// countsIndex returns the index of the bucket counting v.
+func countsIndex(v int64) int {
+	bucket := 64 - bits.LeadingZeros64(uint64(v)|subBucketMask) - (subBucketHalfMag + 1)
+	subBucket := int(v >> bucket)
+	return (bucket+1)<<subBucketHalfMag + subBucket - subBucketHalfCount
+}
+
+// highestEquivalentValue returns the highest value counted at index i.
+func highestEquivalentValue(i int) int64 {
+	bucket := i>>subBucketHalfMag - 1
+	subBucket := i&(subBucketHalfCount-1) + subBucketHalfCount
+	if bucket < 0 {
+		subBucket -= subBucketHalfCount
+		bucket = 0
+	}
+	lowest := int64(subBucket) << bucket
+	return lowest + int64(1)<<bucket - 1
+}
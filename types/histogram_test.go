@@ -0,0 +1,50 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramPercentiles(t *testing.T) {
+	h := NewHistogram()
+	for i := 1; i <= 10000; i++ {
+		h.Record(time.Duration(i) * time.Millisecond)
+	}
+	s := h.Summary()
+	require.Equal(t, 10000, s.Count)
+	require.Equal(t, time.Millisecond, s.Min)
+	require.Equal(t, 10*time.Second, s.Max)
+	require.InEpsilon(t, float64(5*time.Second), float64(s.P50), 1e-3)
+	require.InEpsilon(t, float64(9*time.Second), float64(s.P90), 1e-3)
+	require.InEpsilon(t, float64(9900*time.Millisecond), float64(s.P99), 1e-3)
+	require.InEpsilon(t, float64(9990*time.Millisecond), float64(s.P999), 1e-3)
+}
+
+func TestHistogramMerge(t *testing.T) {
+	round1, round2, total := NewHistogram(), NewHistogram(), NewHistogram()
+	for i := 1; i <= 100; i++ {
+		round1.Record(time.Duration(i) * time.Microsecond)
+		round2.Record(time.Duration(i) * time.Second)
+	}
+	total.Merge(round1)
+	total.Merge(round2)
+	s := total.Summary()
+	require.Equal(t, 200, s.Count)
+	require.Equal(t, time.Microsecond, s.Min)
+	require.Equal(t, 100*time.Second, s.Max)
+	require.Equal(t, 100*time.Microsecond, s.P50)
+	require.InEpsilon(t, float64(98*time.Second), float64(s.P99), 1e-3)
+
+	round1.Reset()
+	require.Zero(t, round1.Count())
+	require.Equal(t, LatencySummary{}, round1.Summary())
+}
+
+func TestHistogramClampsHighValues(t *testing.T) {
+	h := NewHistogram()
+	h.Record(3 * time.Hour)
+	require.Equal(t, 3*time.Hour, h.Summary().Max)
+	require.Equal(t, 3*time.Hour, h.Percentile(0.5))
+}
@@ -0,0 +1,12 @@
+package types
+
+// InclusionStats describes how many of the sent txs made it into a block and
+// how long it took from submission to inclusion.
+type InclusionStats struct {
+	Tracked  int64          `json:"tracked"`
+	Included int64          `json:"included"`
+	Reverted int64          `json:"reverted"` // included with a failed status
+	Pending  int64          `json:"pending"`  // not included when tracking stopped
+	GasUsed  uint64         `json:"gas_used"`
+	Latency  LatencySummary `json:"latency"` // submission to inclusion
+}
@@ -0,0 +1,14 @@
+package types
+
+import "time"
+
+// Interval is a round of a run, i.e. one time unit worth of txs.
+type Interval struct {
+	Start  time.Time `json:"start"`
+	Sent   int       `json:"sent"` // accepted by the node
+	Failed int64     `json:"failed"`
+	// Written counts the fire-and-forget txs whose response wasn't awaited
+	Written int64 `json:"written,omitempty"`
+	// Latency is the send latency of the round
+	Latency LatencySummary `json:"latency"`
+}
@@ -1,5 +1,7 @@
 package types
 
+import "encoding/json"
+
 type ErrResponse struct {
 	Jsonrpc string `json:"jsonrpc"`
 	Id      int    `json:"id"`
@@ -14,3 +16,15 @@ type Response struct {
 	Id      int         `json:"id"`
 	Result  interface{} `json:"result"`
 }
+
+// RawResponse is a json-rpc response whose result is decoded by the caller.
+type RawResponse struct {
+	Jsonrpc string          `json:"jsonrpc"`
+	Id      int             `json:"id"`
+	Result  json.RawMessage `json:"result"`
+	Error   *struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data,omitempty"`
+	} `json:"error"`
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// LatencySummary holds the percentiles of a set of latencies.
+type LatencySummary struct {
+	Count int           `json:"count"`
+	Min   time.Duration `json:"min"`
+	Mean  time.Duration `json:"mean"`
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	P99   time.Duration `json:"p99"`
+	P999  time.Duration `json:"p99_9"`
+	Max   time.Duration `json:"max"`
+}
+
+// SummarizeLatencies sorts latencies in place and returns their percentiles.
+func SummarizeLatencies(latencies []time.Duration) LatencySummary {
+	if len(latencies) == 0 {
+		return LatencySummary{}
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	var total time.Duration
+	for _, l := range latencies {
+		total += l
+	}
+	percentile := func(p float64) time.Duration {
+		return latencies[int(p*float64(len(latencies)-1)+0.5)]
+	}
+	return LatencySummary{
+		Count: len(latencies),
+		Min:   latencies[0],
+		Mean:  total / time.Duration(len(latencies)),
+		P50:   percentile(0.50),
+		P90:   percentile(0.90),
+		P99:   percentile(0.99),
+		P999:  percentile(0.999),
+		Max:   latencies[len(latencies)-1],
+	}
+}
+
+// RequestLatency holds the latency of every tx submission of a scenario, from
+// the start of the send until the response, retries included.
+type RequestLatency struct {
+	Scenario string `json:"scenario"`
+	// Total merges the histograms of every round, see Interval for the
+	// latency of each round
+	Total LatencySummary `json:"total"`
+	// Endpoints holds the latency of single send attempts per endpoint
+	Endpoints map[string]LatencySummary `json:"endpoints,omitempty"`
+}
@@ -0,0 +1,18 @@
+package types
+
+import "time"
+
+const (
+	MempoolSourceTxpool   = "txpool"   // txpool_status of the json-rpc server
+	MempoolSourceCometBFT = "cometbft" // num_unconfirmed_txs of cometbft rpc
+)
+
+// MempoolSample is the mempool occupancy at a point in time. Queued is only
+// reported by txpool_status and Bytes only by cometbft.
+type MempoolSample struct {
+	Time    time.Time `json:"time"`
+	Source  string    `json:"source"`
+	Pending int64     `json:"pending"`
+	Queued  int64     `json:"queued"`
+	Bytes   int64     `json:"bytes"`
+}
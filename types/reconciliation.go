@@ -0,0 +1,34 @@
+package types
+
+import "github.com/ethereum/go-ethereum/common"
+
+// SentTx is a tx accepted by the node.
+type SentTx struct {
+	Hash  common.Hash    `json:"hash"`
+	From  common.Address `json:"from"`
+	Nonce uint64         `json:"nonce"`
+}
+
+// TxState is the fate of an accepted tx as seen after the run.
+type TxState string
+
+const (
+	TxIncluded         TxState = "included"
+	TxIncludedReverted TxState = "included_reverted"
+	// still known to the node but not in a block
+	TxPending TxState = "pending"
+	// unknown to the node and its nonce slot is still unused
+	TxDropped TxState = "dropped"
+	// unknown to the node but another tx took its nonce slot
+	TxReplaced TxState = "replaced"
+	// the state couldn't be queried
+	TxUnknown TxState = "unknown"
+)
+
+// Reconciliation classifies every accepted tx against the chain state.
+type Reconciliation struct {
+	Counts map[TxState]int64 `json:"counts"`
+	// Pending and Dropped list the affected senders and nonces
+	Pending []SentTx `json:"pending,omitempty"`
+	Dropped []SentTx `json:"dropped,omitempty"`
+}
@@ -0,0 +1,11 @@
+package types
+
+// RetryStats separates failures fixed by retrying from the ones of the first attempt.
+type RetryStats struct {
+	FirstAttemptFailures int64 `json:"first_attempt_failures"`
+	RetriedTxs           int64 `json:"retried_txs"`    // txs sent more than once
+	RetryAttempts        int64 `json:"retry_attempts"` // attempts after the first one
+	Recovered            int64 `json:"recovered"`      // txs which succeeded on a retry
+	AlreadyKnownOnRetry  int64 `json:"already_known_on_retry"`
+	Exhausted            int64 `json:"exhausted"` // txs still failing on the last attempt
+}
@@ -0,0 +1,138 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorCategory classifies why a transaction submission failed.
+type ErrorCategory string
+
+const (
+	CategoryTransport        ErrorCategory = "transport"         // connection refused, reset, timeout
+	CategoryNoEndpoint       ErrorCategory = "no_endpoint"       // every endpoint is marked down
+	CategoryHttpStatus       ErrorCategory = "http_status"       // non 200 response without json-rpc body
+	CategoryInvalidResponse  ErrorCategory = "invalid_response"  // response body is not json-rpc
+	CategoryHashMismatch     ErrorCategory = "hash_mismatch"     // node returned another tx hash
+	CategoryInvalidNonce     ErrorCategory = "invalid_nonce"     // nonce too low or too high
+	CategoryInsufficientFund ErrorCategory = "insufficient_fund" // balance < value + gas * price
+	CategoryAlreadyKnown     ErrorCategory = "already_known"     // tx is already in the mempool
+	CategoryUnderpriced      ErrorCategory = "underpriced"       // gas price or fee too low
+	CategoryTxTooLarge       ErrorCategory = "tx_too_large"      // tx exceeds the size limit
+	CategoryMempoolFull      ErrorCategory = "mempool_full"      // mempool rejected the tx for capacity
+	CategoryGas              ErrorCategory = "gas"               // intrinsic gas too low or above block limit
+	CategoryInvalidTx        ErrorCategory = "invalid_tx"        // signature, chain id or encoding
+	CategoryInvalidRequest   ErrorCategory = "invalid_request"   // parse error, unknown method, bad params
+	CategoryRpcOther         ErrorCategory = "rpc_other"         // any other json-rpc error
+	CategoryOther            ErrorCategory = "other"             // any other error, never retried
+)
+
+// JSON-RPC 2.0 reserved error codes
+const (
+	RpcCodeParseError     = -32700
+	RpcCodeInvalidRequest = -32600
+	RpcCodeMethodNotFound = -32601
+	RpcCodeInvalidParams  = -32602
+)
+
+// rpcErrorPatterns maps lowercased message fragments of geth, ethermint and
+// cometbft to their category. The first match wins.
+var rpcErrorPatterns = []struct {
+	fragment string
+	category ErrorCategory
+}{
+	{"already known", CategoryAlreadyKnown},
+	{"already in mempool", CategoryAlreadyKnown},
+	{"already exists in cache", CategoryAlreadyKnown},
+	{"invalid nonce", CategoryInvalidNonce},
+	{"nonce too low", CategoryInvalidNonce},
+	{"nonce too high", CategoryInvalidNonce},
+	{"insufficient fund", CategoryInsufficientFund},
+	{"underpriced", CategoryUnderpriced},
+	{"gas price too low", CategoryUnderpriced},
+	{"insufficient fee", CategoryUnderpriced},
+	{"less than block base fee", CategoryUnderpriced},
+	{"minimum global fee", CategoryUnderpriced},
+	{"tx too large", CategoryTxTooLarge},
+	{"oversized data", CategoryTxTooLarge},
+	{"tx too big", CategoryTxTooLarge},
+	{"mempool is full", CategoryMempoolFull},
+	{"txpool is full", CategoryMempoolFull},
+	{"intrinsic gas too low", CategoryGas},
+	{"exceeds block gas limit", CategoryGas},
+	{"out of gas", CategoryGas},
+	{"invalid sender", CategoryInvalidTx},
+	{"invalid chain id", CategoryInvalidTx},
+	{"replay-protected", CategoryInvalidTx},
+	{"rlp", CategoryInvalidTx},
+}
+
+// ClassifyRpcError returns the category of a json-rpc error.
+func ClassifyRpcError(code int, message string) ErrorCategory {
+	msg := strings.ToLower(message)
+	for _, p := range rpcErrorPatterns {
+		if strings.Contains(msg, p.fragment) {
+			return p.category
+		}
+	}
+	switch code {
+	case RpcCodeParseError, RpcCodeInvalidRequest, RpcCodeMethodNotFound, RpcCodeInvalidParams:
+		return CategoryInvalidRequest
+	}
+	return CategoryRpcOther
+}
+
+// RpcError is a failed submission along with its json-rpc (or http) code.
+type RpcError struct {
+	Code     int
+	Message  string
+	Category ErrorCategory
+}
+
+func (e *RpcError) Error() string {
+	return fmt.Sprintf("%s (code %d): %s", e.Category, e.Code, e.Message)
+}
+
+// Unwrap keeps errors.Is working against the sentinel errors of this package.
+func (e *RpcError) Unwrap() error {
+	if e.Category == CategoryInsufficientFund {
+		return ErrorInsufficientFund
+	}
+	return nil
+}
+
+// CategoryOf returns the category of any error returned by a requester.
+func CategoryOf(err error) ErrorCategory {
+	var rpcErr *RpcError
+	var nonceErr *NonceError
+	var transportErr *TransportError
+	switch {
+	case errors.As(err, &nonceErr):
+		return CategoryInvalidNonce
+	case errors.As(err, &rpcErr):
+		return rpcErr.Category
+	case errors.As(err, &transportErr):
+		return CategoryTransport
+	case errors.Is(err, ErrorNoHealthyEndpoint):
+		return CategoryNoEndpoint
+	case errors.Is(err, ErrorInsufficientFund):
+		return CategoryInsufficientFund
+	default:
+		return CategoryOther
+	}
+}
+
+// TransportError is a failure to reach the node or to read its response, the
+// node may not have received the tx.
+type TransportError struct {
+	Err error
+}
+
+func (e *TransportError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,31 @@
+package types
+
+import "time"
+
+// Stage is a step of the lifecycle of a tx.
+type Stage string
+
+const (
+	// signing the tx and building its request body
+	StageSigning Stage = "signing"
+	// from the start of the batch to the start of the send
+	StageQueueing Stage = "queueing"
+	// round trip of the send, retries included
+	StageSend Stage = "send"
+	// from the start of the send until the node returns the tx by hash
+	StageMempoolVisibility Stage = "mempool_visibility"
+	// from the start of the send until the tx is seen in a block
+	StageInclusion Stage = "inclusion"
+)
+
+// Stages lists the stages in lifecycle order.
+var Stages = []Stage{StageSigning, StageQueueing, StageSend, StageMempoolVisibility, StageInclusion}
+
+// SendTiming is when the send of a tx started and when it returned.
+type SendTiming struct {
+	Start time.Time
+	End   time.Time
+	// Written is set when the tx was written without waiting for the response
+	// of the node, which may still reject it
+	Written bool
+}
@@ -0,0 +1,19 @@
+package types
+
+import "time"
+
+// ThroughputStats is the on-chain throughput over a range of blocks, as
+// opposed to the client-side send rate.
+type ThroughputStats struct {
+	FromBlock   uint64        `json:"from_block"` // excluded, its timestamp starts the range
+	ToBlock     uint64        `json:"to_block"`
+	Blocks      int           `json:"blocks"`
+	Txs         int64         `json:"txs"`
+	OurTxs      int64         `json:"our_txs"`      // txs sent by this run
+	OurFraction float64       `json:"our_fraction"` // OurTxs / Txs
+	GasUsed     uint64        `json:"gas_used"`
+	Duration    time.Duration `json:"duration"` // between the timestamps of FromBlock and ToBlock
+	Tps         float64       `json:"tps"`
+	TxsPerBlock float64       `json:"txs_per_block"`
+	GasPerBlock float64       `json:"gas_per_block"`
+}
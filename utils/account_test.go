@@ -1,4 +1,4 @@
-package utils
+package utils_test
 
 import (
 	"fmt"
@@ -7,8 +7,9 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/require"
 
-	"loadtester/cmd"
+	"loadtester/cmd/evmtx"
 	types2 "loadtester/types"
+	"loadtester/utils"
 )
 
 func TestSelectAccountsToUse(t *testing.T) {
@@ -26,7 +27,7 @@ func TestSelectAccountsToUse(t *testing.T) {
 	// Test the function
 	for _, tc := range tcs {
 		expectedAccountsToUse := append(accounts[tc.startIdx:tc.startIdx+tc.tps], accounts[:tc.tps]...)
-		accountsToUse := SelectAccountsToUse(tc.tps, accounts, tc.startIdx, "senders")
+		accountsToUse := utils.SelectAccountsToUse(tc.tps, accounts, tc.startIdx, "senders")
 		require.Equal(t, tc.tps, len(accountsToUse), "Expected %d accounts to use, got %d", tc.tps, len(accountsToUse))
 		for i := 0; i < tc.tps; i++ {
 			require.Equal(t, expectedAccountsToUse[i], accountsToUse[i], "Expected account %v, got %v", expectedAccountsToUse[i], accountsToUse[i])
@@ -35,8 +36,8 @@ func TestSelectAccountsToUse(t *testing.T) {
 }
 
 func TestSelectAccInfosForNode(t *testing.T) {
-	cfg := cmd.DefaultConfig()
-	accInfos := createDummyAccInfos(cfg.EvmTxConfig.AccNum)
+	cfg := evmtx.DefaultConfig()
+	accInfos := createDummyAccInfos(cfg.AccNum)
 	require.Len(t, accInfos, 100)
 
 	tcs := []struct {
@@ -51,7 +52,7 @@ func TestSelectAccInfosForNode(t *testing.T) {
 
 	// Test the function
 	for _, tc := range tcs {
-		selected := SelectAccInfosForNode(tc.nodeNum, tc.nodeIdx, accInfos)
+		selected := utils.SelectAccInfosForNode(tc.nodeNum, tc.nodeIdx, accInfos)
 		require.Equal(t, tc.expected, selected, "Expected %v, got %v", tc.expected, selected)
 	}
 }
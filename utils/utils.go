@@ -89,3 +89,26 @@ func TestEnded(end time.Time) bool {
 	}
 	return false
 }
+
+// RawTxFromReqBody extracts the signed transaction of an eth_sendRawTransaction request body.
+func RawTxFromReqBody(reqBody []byte) ([]byte, error) {
+	var req struct {
+		Params []string `json:"params"`
+	}
+	if err := json.Unmarshal(reqBody, &req); err != nil {
+		return nil, err
+	}
+	if len(req.Params) != 1 {
+		return nil, errors.New("eth_sendRawTransaction takes exactly one param")
+	}
+	return hexutil.Decode(req.Params[0])
+}
+
+// TxHashFromReqBody computes the hash of the transaction in an eth_sendRawTransaction request body.
+func TxHashFromReqBody(reqBody []byte) (common.Hash, error) {
+	rawTx, err := RawTxFromReqBody(reqBody)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return crypto.Keccak256Hash(rawTx), nil
+}